@@ -1,24 +1,84 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"jetfind/internal/cli"
+	"jetfind/internal/config"
 	"jetfind/internal/tui"
 	"os"
 )
 
+// Exit codes, compatible with fzf.
+const (
+	exitNoMatch     = 1
+	exitError       = 2
+	exitInterrupted = 130
+)
+
 func main() {
+	if handled, err := cli.RunSubcommand(os.Args[1:], os.Stdout); handled {
+		if err != nil {
+			cli.PrintError(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
 	cliFalgs := cli.ParseArgs()
 
-	selectedFile, err := tui.Run()
+	cfg, err := config.LoadLayered(cliFalgs.Profile, cli.ApproveProject)
+	if err != nil {
+		cli.PrintError(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	for _, w := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	reload := cliFalgs.Reloader()
+	if err := cliFalgs.ApplyTo(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid arguments: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	if cliFalgs.IsFilterMode() {
+		results, _, err := cli.RunFilter(cfg, "./", cliFalgs.Filter, cliFalgs.Limit, cliFalgs.Dirs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Filter error: %v\n", err)
+			os.Exit(exitError)
+		}
+		if err := cli.NewExecutor(cliFalgs).PrintResults(results); err != nil {
+			fmt.Fprintf(os.Stderr, "Output error: %v\n", err)
+			os.Exit(exitError)
+		}
+		if len(results) == 0 {
+			os.Exit(exitNoMatch)
+		}
+		return
+	}
+
+	selected, err := tui.Run(cfg, tui.Options{
+		Dirs:      cliFalgs.Dirs,
+		SelectOne: cliFalgs.SelectOne,
+		ExitZero:  cliFalgs.ExitZero,
+		Query:     cliFalgs.Query,
+		Reload:    reload,
+		Watch:     config.WatchedFiles(),
+	})
+	if errors.Is(err, tui.ErrAborted) {
+		os.Exit(exitInterrupted)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "TUI error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+	if len(selected) == 0 {
+		os.Exit(exitNoMatch)
 	}
 
 	exec := cli.NewExecutor(cliFalgs)
-	if err := exec.Execute(selectedFile); err != nil {
+	if err := exec.Execute(selected); err != nil {
 		fmt.Fprintf(os.Stderr, "Execution error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
@@ -1,21 +1,81 @@
 package cli
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"jetfind/internal/config"
+	"jetfind/internal/keymap"
+	"jetfind/internal/scanengine"
+	"math"
 	"os"
+	"slices"
+	"strconv"
 )
 
 type CliFlags struct {
-	PostCmd string
-	Help    bool
-	Version bool
+	PostCmd     string
+	Filter      string
+	Limit       int
+	Query       string
+	Profile     string
+	FilterType  string
+	Algo        string
+	Threshold   *float64
+	Scope       string
+	Tiebreak    string
+	History     string
+	HistorySize int
+	Bind        []string
+	Height      string
+	Layout      string
+	Dirs        bool
+	Quote       string
+	Print0      bool
+	Output      string
+	SelectOne   bool
+	ExitZero    bool
+	Help        bool
+	Version     bool
 }
 
 func ParseArgs() *CliFlags {
 	config := &CliFlags{}
 
 	flag.StringVar(&config.PostCmd, "post-cmd", "", "Command to execute after a file has been selected")
+	flag.StringVar(&config.Filter, "filter", "", "Print the paths matching the query without starting the TUI")
+	flag.IntVar(&config.Limit, "limit", 0, "Maximum number of results printed in filter mode (0 means no limit)")
+	flag.StringVar(&config.Query, "query", "", "Start the TUI with this query")
+	flag.StringVar(&config.Profile, "profile", "", "Apply the settings of this configuration profile (default $JETFIND_PROFILE)")
+	flag.StringVar(&config.FilterType, "filter-type", "", "Filter type: fuzzy or contains")
+	flag.StringVar(&config.Algo, "algo", "", "Fuzzy algorithm: jarowinkler, ngram or levenshtein")
+	flag.Func("threshold", "Minimum fuzzy score, in the [0, 1] interval", func(s string) error {
+		threshold, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		if math.IsNaN(threshold) {
+			return errors.New("not a number")
+		}
+		config.Threshold = &threshold
+		return nil
+	})
+	flag.StringVar(&config.Scope, "scope", "", "Part of the paths matched: auto, basename, path or relative-path")
+	flag.StringVar(&config.Tiebreak, "tiebreak", "", "Comma separated sort criteria: score, length, depth, path, mtime, index")
+	flag.StringVar(&config.History, "history", "", "File where submitted queries are stored")
+	flag.IntVar(&config.HistorySize, "history-size", 0, "Maximum number of stored queries (a negative value disables the history)")
+	flag.Func("bind", "Comma separated key bindings, e.g. 'ctrl-y:copy-path,ctrl-o:execute(vim {})' (repeatable)", func(s string) error {
+		config.Bind = append(config.Bind, s)
+		return nil
+	})
+	flag.StringVar(&config.Height, "height", "", "Draw the TUI below the prompt using this many rows, or a percentage of the terminal (e.g. 40%)")
+	flag.StringVar(&config.Layout, "layout", "", "TUI layout: default, or reverse to put the query at the bottom")
+	flag.BoolVar(&config.Dirs, "dirs", false, "Select directories instead of files")
+	flag.StringVar(&config.Quote, "quote", "", "Print the selected paths quoted for a shell (sh, bash, zsh, fish) on a single line")
+	flag.BoolVar(&config.Print0, "print0", false, "Terminate the printed paths with NUL instead of newline")
+	flag.StringVar(&config.Output, "output", OutputText, "Output format: text, or json for the paths with their score, root and file metadata")
+	flag.BoolVar(&config.SelectOne, "select-1", false, "Select the only match without starting the interaction")
+	flag.BoolVar(&config.ExitZero, "exit-0", false, "Exit at once when nothing matches")
 	flag.BoolVar(&config.Help, "help", false, "Show help message")
 	flag.BoolVar(&config.Help, "h", false, "Show help message")
 	flag.BoolVar(&config.Version, "version", false, "Show version information")
@@ -23,11 +83,24 @@ func ParseArgs() *CliFlags {
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "A configurable file finder with interactive selection.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  config init|show|validate [path]|migrate [path]|path\n")
+		fmt.Fprintf(os.Stderr, "        Write, print with the origin of each setting, check or upgrade the configuration\n")
+		fmt.Fprintf(os.Stderr, "  frecency list|forget <path>...|clear\n")
+		fmt.Fprintf(os.Stderr, "        Inspect and manage the data used by the frecency ranking\n")
+		fmt.Fprintf(os.Stderr, "  shell-init bash|zsh|fish\n")
+		fmt.Fprintf(os.Stderr, "        Print the key bindings and completion for a shell\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExit status:\n")
+		fmt.Fprintf(os.Stderr, "  0 a path was selected, 1 no match, 2 error, 130 interrupted with ctrl+c or esc\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s		      Select and print file path\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --post-cmd vim    Open selected file with vim\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --height 40%% --layout reverse\n                      Pick a file below the prompt, query at the bottom\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --query main --filter-type contains\n                      Start with 'main' typed in, matching substrings\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --profile docs    Search with the settings of the 'docs' profile\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --filter main --limit 10\n                      Print the 10 best matches for 'main'\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -48,3 +121,137 @@ func ParseArgs() *CliFlags {
 func (c *CliFlags) HasPostCommand() bool {
 	return c.PostCmd != ""
 }
+
+func (c *CliFlags) IsFilterMode() bool {
+	return c.Filter != ""
+}
+
+// ApplyTo overrides the loaded configuration with the values given on the
+// command line.
+func (c *CliFlags) ApplyTo(cfg *config.Config) error {
+	if c.PostCmd != "" {
+		cfg.PostCmd = c.PostCmd
+		setByFlag(cfg, "post_cmd", "post-cmd")
+	}
+	// The executor runs the post command of the flags.
+	c.PostCmd = cfg.PostCmd
+	if c.FilterType != "" {
+		if !slices.Contains(scanengine.FilterTypes, c.FilterType) {
+			return fmt.Errorf("invalid filter type: %s. Must be one of: %v", c.FilterType, scanengine.FilterTypes)
+		}
+		cfg.Filter.Type = c.FilterType
+		setByFlag(cfg, "filter.type", "filter-type")
+	}
+	if c.Algo != "" {
+		if !slices.Contains(scanengine.Algos, c.Algo) {
+			return fmt.Errorf("invalid filter algorithm: %s. Must be one of: %v", c.Algo, scanengine.Algos)
+		}
+		cfg.Filter.Algo = c.Algo
+		setByFlag(cfg, "filter.algorithm", "algo")
+	}
+	if c.Threshold != nil {
+		if math.IsNaN(*c.Threshold) || *c.Threshold < 0 || *c.Threshold > 1 {
+			return fmt.Errorf("invalid filter threshold: %.2f. Must be in the [0, 1] interval", *c.Threshold)
+		}
+		cfg.Filter.Threshold = *c.Threshold
+		setByFlag(cfg, "filter.threshold", "threshold")
+	}
+	if c.Scope != "" {
+		if !slices.Contains(scanengine.Scopes, c.Scope) {
+			return fmt.Errorf("invalid filter scope: %s. Must be one of: %v", c.Scope, scanengine.Scopes)
+		}
+		cfg.Filter.Scope = c.Scope
+		setByFlag(cfg, "filter.scope", "scope")
+	}
+	if c.Tiebreak != "" {
+		order, err := scanengine.ParseSortOrder(c.Tiebreak)
+		if err != nil {
+			return err
+		}
+		cfg.Filter.Tiebreak = order
+		setByFlag(cfg, "filter.tiebreak", "tiebreak")
+	}
+	if c.History != "" {
+		cfg.History.File = c.History
+		setByFlag(cfg, "history.file", "history")
+	}
+	if c.HistorySize != 0 {
+		cfg.History.Size = c.HistorySize
+		setByFlag(cfg, "history.size", "history-size")
+	}
+	if c.Height != "" {
+		if _, _, err := config.ParseHeight(c.Height); err != nil {
+			return err
+		}
+		cfg.Tui.Height = c.Height
+		setByFlag(cfg, "tui.height", "height")
+	}
+	if c.Layout != "" {
+		if c.Layout != config.LayoutDefault && c.Layout != config.LayoutReverse {
+			return fmt.Errorf("invalid layout: %s. Must be one of: [%s %s]", c.Layout, config.LayoutDefault, config.LayoutReverse)
+		}
+		cfg.Tui.Layout = c.Layout
+		setByFlag(cfg, "tui.layout", "layout")
+	}
+	if _, ok := quoters[c.Quote]; c.Quote != "" && !ok {
+		return fmt.Errorf("invalid quote shell: %s. Must be one of: [sh bash zsh fish]", c.Quote)
+	}
+	if c.Output != "" && c.Output != OutputText && c.Output != OutputJSON {
+		return fmt.Errorf("invalid output: %s. Must be one of: [%s %s]", c.Output, OutputText, OutputJSON)
+	}
+	formats := 0
+	for _, set := range []bool{c.Print0, c.Quote != "", c.Output == OutputJSON} {
+		if set {
+			formats++
+		}
+	}
+	if formats > 1 {
+		return fmt.Errorf("--print0, --quote and --output json cannot be combined")
+	}
+	for _, spec := range c.Bind {
+		bindings, err := keymap.ParseBind(spec)
+		if err != nil {
+			return err
+		}
+		applyBindings(cfg, bindings)
+		setByFlag(cfg, "tui.keys", "bind")
+	}
+	return nil
+}
+
+// Reloader returns a function loading the configuration again and applying
+// the flags of c over it, for the TUI to follow the changes of the files.
+// It must be taken before ApplyTo, which updates c. Project files are not
+// asked for approval then, since the TUI owns the terminal.
+func (c CliFlags) Reloader() func() (*config.Config, error) {
+	return func() (*config.Config, error) {
+		cfg, err := config.LoadLayered(c.Profile, TrustedProject)
+		if err != nil {
+			return nil, err
+		}
+		flags := c
+		return cfg, flags.ApplyTo(cfg)
+	}
+}
+
+func setByFlag(cfg *config.Config, path, flag string) {
+	cfg.SetOrigin(path, config.Origin{Layer: config.LayerFlag, Source: "--" + flag})
+}
+
+// applyBindings adds bindings to the configured keys, replacing the chords
+// that name the same keys.
+func applyBindings(cfg *config.Config, bindings []keymap.Binding) {
+	keys := make(map[string]string, len(cfg.Tui.Keys)+len(bindings))
+	for chord, expr := range cfg.Tui.Keys {
+		keys[chord] = expr
+	}
+	for _, b := range bindings {
+		for chord := range keys {
+			if key, err := keymap.ParseKey(chord); err == nil && key == b.Key {
+				delete(keys, chord)
+			}
+		}
+		keys[b.Key] = b.Expr
+	}
+	cfg.Tui.Keys = keys
+}
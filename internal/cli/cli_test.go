@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"jetfind/internal/config"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -57,3 +60,98 @@ func TestCliFlagsDefaultValues(t *testing.T) {
 	}
 }
 
+func TestCliFlagsApplyTo(t *testing.T) {
+	cfg := &config.Config{Filter: config.FilterConfig{Tiebreak: []string{"score"}}}
+
+	c := &CliFlags{}
+	if err := c.ApplyTo(cfg); err != nil {
+		t.Fatalf("ApplyTo() without flags returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Filter.Tiebreak, []string{"score"}) {
+		t.Errorf("ApplyTo() without flags changed tiebreak to %v", cfg.Filter.Tiebreak)
+	}
+
+	c = &CliFlags{Tiebreak: "length,path"}
+	if err := c.ApplyTo(cfg); err != nil {
+		t.Fatalf("ApplyTo() returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Filter.Tiebreak, []string{"length", "path"}) {
+		t.Errorf("Expected tiebreak [length path], got %v", cfg.Filter.Tiebreak)
+	}
+
+	c = &CliFlags{Tiebreak: "size"}
+	if err := c.ApplyTo(cfg); err == nil {
+		t.Error("Expected an error for an unknown tiebreak, got none")
+	}
+}
+
+func TestCliFlagsApplyToBind(t *testing.T) {
+	cfg := &config.Config{
+		Filter: config.Default.Filter,
+		Tui:    config.TuiConfig{Keys: map[string]string{"ctrl-y": "copy-path", "f1": "help"}},
+	}
+
+	c := &CliFlags{Bind: []string{"ctrl+y:accept,alt-x:execute(echo {})", "f2:abort"}}
+	if err := c.ApplyTo(cfg); err != nil {
+		t.Fatalf("ApplyTo() returned an error: %v", err)
+	}
+	expected := map[string]string{"ctrl+y": "accept", "f1": "help", "alt+x": "execute(echo {})", "f2": "abort"}
+	if !reflect.DeepEqual(cfg.Tui.Keys, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, cfg.Tui.Keys)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Bound configuration is invalid: %v", err)
+	}
+
+	c = &CliFlags{Bind: []string{"ctrl-y:yank"}}
+	if err := c.ApplyTo(cfg); err == nil {
+		t.Error("Expected an error for an unknown action, got none")
+	}
+}
+
+func TestCliFlagsApplyToDisplayOptions(t *testing.T) {
+	cfg := &config.Config{}
+
+	c := &CliFlags{Height: "40%", Layout: "reverse"}
+	if err := c.ApplyTo(cfg); err != nil {
+		t.Fatalf("ApplyTo() returned an error: %v", err)
+	}
+	if cfg.Tui.Height != "40%" || cfg.Tui.Layout != "reverse" {
+		t.Errorf("Expected height 40%% and layout reverse, got %q and %q", cfg.Tui.Height, cfg.Tui.Layout)
+	}
+
+	for _, c := range []*CliFlags{{Height: "0"}, {Height: "half"}, {Layout: "upside-down"}, {Quote: "tcsh"}, {Output: "xml"}, {Print0: true, Output: "json"}, {Print0: true, Quote: "bash"}} {
+		if err := c.ApplyTo(cfg); err == nil {
+			t.Errorf("Expected an error for %+v, got none", *c)
+		}
+	}
+}
+
+func TestCliFlagsApplyToFilterOptions(t *testing.T) {
+	cfg := &config.Config{Filter: config.Default.Filter}
+
+	threshold := 0.5
+	c := &CliFlags{FilterType: "contains", Algo: "ngram", Threshold: &threshold, Scope: "relative-path"}
+	if err := c.ApplyTo(cfg); err != nil {
+		t.Fatalf("ApplyTo() returned an error: %v", err)
+	}
+	if cfg.Filter.Type != "contains" || cfg.Filter.Algo != "ngram" || cfg.Filter.Threshold != 0.5 || cfg.Filter.Scope != "relative-path" {
+		t.Errorf("Expected contains, ngram, 0.5 and relative-path, got %s, %s, %.2f and %s", cfg.Filter.Type, cfg.Filter.Algo, cfg.Filter.Threshold, cfg.Filter.Scope)
+	}
+	if origin := cfg.Origin("filter.algorithm"); origin != (config.Origin{Layer: config.LayerFlag, Source: "--algo"}) {
+		t.Errorf("Expected the algorithm to come from --algo, got %v", origin)
+	}
+
+	zero := 0.0
+	c = &CliFlags{Threshold: &zero}
+	if err := c.ApplyTo(cfg); err != nil || cfg.Filter.Threshold != 0 {
+		t.Errorf("Expected a zero threshold to be applied, got %.2f (%v)", cfg.Filter.Threshold, err)
+	}
+
+	outOfRange, nan := 1.5, math.NaN()
+	for _, c := range []*CliFlags{{FilterType: "regex"}, {Algo: "soundex"}, {Threshold: &outOfRange}, {Threshold: &nan}, {Scope: "dirname"}} {
+		if err := c.ApplyTo(cfg); err == nil {
+			t.Errorf("Expected an error for %+v, got none", *c)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jetfind/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const configUsage = "usage: jetfind config init|show|validate [path]|migrate [path]|path"
+
+// runConfig writes, prints and checks the configuration files.
+func runConfig(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(configUsage)
+	}
+
+	path, err := config.GetConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "init" && len(args) == 1:
+		return configInit(path, out)
+	case args[0] == "show" && len(args) == 1:
+		// Showing the configuration does not ask for nor record approvals;
+		// the commands of an unapproved project file are left out.
+		cfg, err := config.LoadLayered("", TrustedProject)
+		if err != nil {
+			return err
+		}
+		data, err := cfg.Annotated()
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	case args[0] == "validate" && len(args) <= 2:
+		return configValidate(args[1:], out)
+	case args[0] == "migrate" && len(args) <= 2:
+		if len(args) == 2 {
+			path = args[1]
+		}
+		return configMigrate(path, out)
+	case args[0] == "path" && len(args) == 1:
+		_, err := fmt.Fprintln(out, path)
+		return err
+	}
+	return errors.New(configUsage)
+}
+
+// configInit writes the commented defaults to path, unless it exists.
+func configInit(path string, out io.Writer) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("config file already exists: %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, config.Template, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	_, err := fmt.Fprintf(out, "Created %s\n", path)
+	return err
+}
+
+// configValidate checks the file at args[0], or the merged configuration,
+// with each of its profiles, and prints every problem. Commands of project
+// files are checked without asking for their approval, since nothing is
+// run.
+func configValidate(args []string, out io.Writer) error {
+	load := func(profile string) (*config.Config, error) {
+		if len(args) == 1 {
+			return config.LoadLayers([]config.FileLayer{{Layer: config.LayerUser, Path: args[0]}}, nil, profile, nil)
+		}
+		return config.LoadLayers(config.Layers(), os.Environ(), profile, func(string, []byte, []string) bool { return true })
+	}
+	if len(args) == 1 {
+		if _, err := os.Stat(args[0]); err != nil {
+			return fmt.Errorf("config file does not exist: %s", args[0])
+		}
+	}
+
+	cfg, err := load("")
+	if err != nil {
+		return err
+	}
+	// Each profile is reported with the problems of its own settings.
+	var errs config.Errors
+	for _, name := range cfg.ProfileNames() {
+		_, err := load(name)
+		var profileErrs config.Errors
+		if !errors.As(err, &profileErrs) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, e := range profileErrs {
+			if !strings.HasPrefix(e.Path, "profiles.") {
+				e.Path = "profiles." + name + "." + e.Path
+			}
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, w := range cfg.Warnings() {
+		fmt.Fprintf(out, "Warning: %s\n", w)
+	}
+	_, err = fmt.Fprintln(out, "Configuration is valid")
+	return err
+}
+
+// configMigrate upgrades the file at path to the current version, keeping
+// the original next to it with a .bak extension.
+func configMigrate(path string, out io.Writer) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	migrated, _, err := config.Migrate(data, path)
+	if err != nil {
+		return err
+	}
+	if migrated == nil {
+		_, err := fmt.Fprintf(out, "%s is up to date\n", path)
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".bak", data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "Migrated %s to version %d, the original is in %s.bak\n", path, config.CurrentVersion, path)
+	return err
+}
+
+// PrintError prints err to w, listing the problems of a configuration one
+// per line.
+func PrintError(w io.Writer, err error) {
+	var errs config.Errors
+	if errors.As(err, &errs) {
+		fmt.Fprintf(w, "Invalid configuration:\n")
+		for _, e := range errs {
+			fmt.Fprintf(w, "  %v\n", e)
+		}
+		return
+	}
+	fmt.Fprintf(w, "%v\n", err)
+}
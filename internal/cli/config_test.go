@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"jetfind/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jetfind", "config.yml")
+
+	var out bytes.Buffer
+	if err := configInit(path, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := config.Load(path); err != nil {
+		t.Errorf("Expected the written file to be valid, obtained %v", err)
+	}
+	if err := configInit(path, &out); err == nil {
+		t.Error("Expected an error for an existing file, got none")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("filter:\n  type: regex\nfrecency:\n  weight: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := configValidate([]string{path}, &out)
+	if err == nil {
+		t.Fatal("Expected an error, got none")
+	}
+	for _, expected := range []string{"invalid filter type", "invalid frecency weight"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q to be reported, obtained %v", expected, err)
+		}
+	}
+
+	// Profiles are checked although none is selected.
+	if err := os.WriteFile(path, []byte("profiles:\n  docs:\n    filter:\n      type: regex\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = configValidate([]string{path}, &out)
+	if err == nil || !strings.HasSuffix(err.Error(), ":4:13: profiles.docs.filter.type: invalid filter type: regex. Must be one of: [fuzzy contains]") {
+		t.Errorf("Expected the profile error, obtained %v", err)
+	}
+
+	if err := runConfig([]string{"validate", "a", "b"}, &out); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("Expected the usage, obtained %v", err)
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	var out bytes.Buffer
+	PrintError(&out, config.Errors{
+		{Path: "filter.type", Source: "config.yml", Line: 2, Column: 9, Message: "invalid filter type: regex"},
+		{Path: "history.size", Source: "JETFIND_HISTORY_SIZE", Message: "cannot unmarshal !!str `lots` into int"},
+	})
+	expected := "Invalid configuration:\n" +
+		"  config.yml:2:9: filter.type: invalid filter type: regex\n" +
+		"  JETFIND_HISTORY_SIZE: history.size: cannot unmarshal !!str `lots` into int\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, obtained %q", expected, out.String())
+	}
+}
+
+func TestConfigMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	original := "filter:\n  threashold: 0.8\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := configMigrate(path, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backup, _ := os.ReadFile(path + ".bak")
+	if string(backup) != original {
+		t.Errorf("Expected the original in the backup, obtained %q", backup)
+	}
+	cfg, err := config.Load(path)
+	if err != nil || cfg.Filter.Threshold != 0.8 || len(cfg.Warnings()) != 0 {
+		t.Errorf("Expected a migrated file without warnings, obtained %v (%v)", cfg.Warnings(), err)
+	}
+
+	out.Reset()
+	if err := configMigrate(path, &out); err != nil || !strings.Contains(out.String(), "up to date") {
+		t.Errorf("Expected the file to be up to date, obtained %q (%v)", out.String(), err)
+	}
+}
@@ -1,39 +1,147 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"jetfind/internal/scanengine"
+	"jetfind/internal/shellquote"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
+)
+
+// quoters quote the printed paths for the shell given with --quote, so that
+// the output can be pasted in a command line as is.
+var quoters = map[string]func(string) string{
+	"sh":   shellquote.Posix,
+	"bash": shellquote.Posix,
+	"zsh":  shellquote.Posix,
+	"fish": shellquote.Fish,
+}
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
 )
 
 type Executor struct {
 	cliFlags CliFlags
+	out      io.Writer
+	root     string
 }
 
 func NewExecutor(cliFlags *CliFlags) *Executor {
+	root, err := filepath.Abs(".")
+	if err != nil {
+		root = "."
+	}
 	return &Executor{
 		cliFlags: *cliFlags,
+		out:      os.Stdout,
+		root:     root,
 	}
 }
 
-func (e *Executor) Execute(selectedFile string) error {
+// Execute prints the selection or runs the post command on it. Nothing
+// happens when nothing was selected.
+func (e *Executor) Execute(selected []scanengine.ScanFilteredResult) error {
+	if len(selected) == 0 {
+		return nil
+	}
+
 	if !e.cliFlags.HasPostCommand() {
-		fmt.Println(selectedFile)
+		return e.PrintResults(selected)
+	}
+
+	paths := make([]string, len(selected))
+	for i, r := range selected {
+		paths[i] = r.Path
+	}
+	return e.executeCommand(paths)
+}
+
+// PrintResults prints results in the format chosen on the command line:
+// one path per line, NUL terminated paths with --print0, quoted paths on
+// a single line with --quote, or a JSON array with --output json.
+func (e *Executor) PrintResults(results []scanengine.ScanFilteredResult) error {
+	switch {
+	case e.cliFlags.Output == OutputJSON:
+		return e.printJSON(results)
+	case e.cliFlags.Quote != "":
+		return e.printQuoted(results, quoters[e.cliFlags.Quote])
+	}
+
+	terminator := "\n"
+	if e.cliFlags.Print0 {
+		terminator = "\x00"
+	}
+	for _, r := range results {
+		if _, err := io.WriteString(e.out, r.Path+terminator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Executor) printQuoted(results []scanengine.ScanFilteredResult, quote func(string) string) error {
+	if len(results) == 0 {
 		return nil
 	}
 
-	return e.executeCommand(selectedFile)
+	quoted := make([]string, len(results))
+	for i, r := range results {
+		quoted[i] = quote(r.Path)
+	}
+	_, err := fmt.Fprintln(e.out, strings.Join(quoted, " "))
+	return err
 }
 
-func (e *Executor) executeCommand(selectedFile string) error {
+type outputResult struct {
+	Path  string      `json:"path"`
+	Score float64     `json:"score"`
+	Root  string      `json:"root"`
+	File  *outputFile `json:"file,omitempty"`
+}
+
+// outputFile is left out when the file cannot be read anymore.
+type outputFile struct {
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+func (e *Executor) printJSON(results []scanengine.ScanFilteredResult) error {
+	output := make([]outputResult, len(results))
+	for i, r := range results {
+		output[i] = outputResult{Path: r.Path, Score: r.Score, Root: e.root}
+		path := r.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(e.root, path)
+		}
+		if info, err := os.Stat(path); err == nil {
+			output[i].File = &outputFile{
+				Size:    info.Size(),
+				Mode:    info.Mode().String(),
+				ModTime: info.ModTime(),
+				IsDir:   info.IsDir(),
+			}
+		}
+	}
+	return json.NewEncoder(e.out).Encode(output)
+}
+
+func (e *Executor) executeCommand(selectedFiles []string) error {
 	cmdParts := strings.Fields(e.cliFlags.PostCmd)
 	if len(cmdParts) == 0 {
 		return fmt.Errorf("empty command")
 	}
 
 	cmdName := cmdParts[0]
-	cmdArgs := append(cmdParts[1:], selectedFile)
+	cmdArgs := append(cmdParts[1:], selectedFiles...)
 
 	cmd := exec.Command(cmdName, cmdArgs...)
 	cmd.Stdin = os.Stdin
@@ -47,4 +155,3 @@ func (e *Executor) executeCommand(selectedFile string) error {
 
 	return nil
 }
-
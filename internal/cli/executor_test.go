@@ -1,6 +1,11 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
+	"jetfind/internal/scanengine"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -29,9 +34,99 @@ func TestExecutorExecuteWithoutPostCommand(t *testing.T) {
 
 	executor := NewExecutor(flags)
 
-	err := executor.Execute("test.txt")
+	err := executor.Execute([]scanengine.ScanFilteredResult{{Path: "test.txt"}})
 	if err != nil {
 		t.Errorf("Execute() without post command should not return error, got: %v", err)
 	}
 }
 
+func TestExecutorPrintsQuotedPaths(t *testing.T) {
+	testCases := []struct {
+		quote    string
+		paths    []string
+		expected string
+	}{
+		{quote: "", paths: []string{"a b", "c"}, expected: "a b\nc\n"},
+		{quote: "bash", paths: []string{"a b", "it's"}, expected: `'a b' 'it'\''s'` + "\n"},
+		{quote: "fish", paths: []string{`it's\`}, expected: `'it\'s\\'` + "\n"},
+		{quote: "zsh", paths: nil, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.quote, func(t *testing.T) {
+			var out bytes.Buffer
+			executor := NewExecutor(&CliFlags{Quote: tc.quote})
+			executor.out = &out
+			if err := executor.Execute(results(tc.paths...)); err != nil {
+				t.Fatalf("Execute() returned an error: %v", err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func results(paths ...string) []scanengine.ScanFilteredResult {
+	r := make([]scanengine.ScanFilteredResult, len(paths))
+	for i, p := range paths {
+		r[i] = scanengine.ScanFilteredResult{Path: p, Score: 1}
+	}
+	return r
+}
+
+func TestExecutorPrint0(t *testing.T) {
+	var out bytes.Buffer
+	executor := NewExecutor(&CliFlags{Print0: true})
+	executor.out = &out
+	if err := executor.PrintResults(results("a\nb", "c")); err != nil {
+		t.Fatalf("PrintResults() returned an error: %v", err)
+	}
+	if out.String() != "a\nb\x00c\x00" {
+		t.Errorf("Expected NUL terminated paths, got %q", out.String())
+	}
+}
+
+func TestExecutorJSONOutput(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	executor := NewExecutor(&CliFlags{Output: OutputJSON})
+	executor.out = &out
+	executor.root = root
+	selected := []scanengine.ScanFilteredResult{{Path: "main.go", Score: 0.75}, {Path: "gone.go", Score: 0.5}}
+	if err := executor.Execute(selected); err != nil {
+		t.Fatalf("Execute() returned an error: %v", err)
+	}
+
+	var decoded []outputResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out.String(), err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(decoded))
+	}
+	if decoded[0].Path != "main.go" || decoded[0].Score != 0.75 || decoded[0].Root != root {
+		t.Errorf("Unexpected first result %+v", decoded[0])
+	}
+	if decoded[0].File == nil || decoded[0].File.Size != 13 || decoded[0].File.IsDir {
+		t.Errorf("Unexpected file metadata %+v", decoded[0].File)
+	}
+	if decoded[1].File != nil {
+		t.Errorf("Expected no metadata for a missing file, got %+v", decoded[1].File)
+	}
+}
+
+func TestExecutorSkipsEmptySelection(t *testing.T) {
+	var out bytes.Buffer
+	e := &Executor{cliFlags: CliFlags{PostCmd: "false", Output: OutputJSON}, out: &out}
+	if err := e.Execute(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, obtained %q", out.String())
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"context"
+	"jetfind/internal/config"
+	"jetfind/internal/frecency"
+	"jetfind/internal/scanengine"
+	"path/filepath"
+	"time"
+)
+
+// RunFilter scans root, or the configured roots, for directories when dirs
+// is set, and filters the results with query without starting the TUI. It
+// returns at most limit results (all when limit <= 0) and the total number
+// of matches.
+func RunFilter(cfg *config.Config, root, query string, limit int, dirs bool) ([]scanengine.ScanFilteredResult, int, error) {
+	fi, err := cfg.Findignore.FindIgnore()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	scanner := scanengine.New(scanengine.Config{
+		Root:       root,
+		Roots:      cfg.ScanRoots(),
+		FindIgnore: fi,
+		Dirs:       dirs,
+	})
+
+	paths := make([]scanengine.ScanFilteredResult, 0)
+	for p := range scanner.Run() {
+		paths = append(paths, scanengine.ScanFilteredResult{Path: p, Score: 1.0})
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		absRoot = root
+	}
+
+	var scanFilter scanengine.ScanFilter = scanengine.NoFilter{}
+	if query != "" {
+		scanFilter = scanengine.NewScanFilter(cfg.ScanFilterOptions(absRoot), query)
+	}
+
+	var boost func(string) float64
+	if cfg.Frecency.Enable {
+		store, err := frecency.Load(cfg.Frecency.GetFrecencyFilePath())
+		if err != nil {
+			return nil, 0, err
+		}
+		boost = store.Boost(absRoot, time.Now())
+	}
+
+	return scanengine.FilterEngineTopK(context.Background(), paths, scanFilter, cfg.Ranking(boost), limit)
+}
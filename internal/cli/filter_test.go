@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"jetfind/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunFilter(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", "main_test.go", "README.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", name, err)
+		}
+	}
+
+	cfg := &config.Config{Filter: config.FilterConfig{Type: "contains"}}
+
+	testCases := []struct {
+		name          string
+		query         string
+		limit         int
+		expectedLen   int
+		expectedTotal int
+	}{
+		{name: "no limit", query: "main", limit: 0, expectedLen: 2, expectedTotal: 2},
+		{name: "limited", query: "main", limit: 1, expectedLen: 1, expectedTotal: 2},
+		{name: "empty query", query: "", limit: 0, expectedLen: 4, expectedTotal: 4},
+		{name: "no match", query: "nomatch", limit: 5, expectedLen: 0, expectedTotal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, total, err := RunFilter(cfg, root, tc.query, tc.limit, false)
+			if err != nil {
+				t.Fatalf("RunFilter() returned an error: %v", err)
+			}
+			if len(results) != tc.expectedLen {
+				t.Errorf("Expected %d results, got %d", tc.expectedLen, len(results))
+			}
+			if total != tc.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tc.expectedTotal, total)
+			}
+		})
+	}
+}
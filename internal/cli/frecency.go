@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jetfind/internal/config"
+	"jetfind/internal/frecency"
+	"path/filepath"
+	"time"
+)
+
+const frecencyUsage = "usage: jetfind frecency list|forget <path>...|clear"
+
+func runFrecency(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(frecencyUsage)
+	}
+
+	cfg, err := config.LoadLayered("", nil)
+	if err != nil {
+		return err
+	}
+	store, err := frecency.Load(cfg.Frecency.GetFrecencyFilePath())
+	if err != nil {
+		return err
+	}
+
+	return frecencyCommand(store, args, out, time.Now())
+}
+
+func frecencyCommand(store *frecency.Store, args []string, out io.Writer, now time.Time) error {
+	switch args[0] {
+	case "list":
+		for _, e := range store.Entries(now) {
+			fmt.Fprintf(out, "%8.2f  %5d  %s  %s\n", e.Frecency(now), e.Count, e.LastUsed.Format(time.DateTime), e.Path)
+		}
+		return nil
+	case "forget":
+		if len(args) < 2 {
+			return errors.New(frecencyUsage)
+		}
+		for _, p := range args[1:] {
+			abs, err := filepath.Abs(p)
+			if err != nil {
+				return err
+			}
+			if !store.Forget(abs) {
+				fmt.Fprintf(out, "not tracked: %s\n", abs)
+			}
+		}
+		return store.Save()
+	case "clear":
+		store.Clear()
+		return store.Save()
+	default:
+		return errors.New(frecencyUsage)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"jetfind/internal/frecency"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrecencyCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frecency.json")
+	store, err := frecency.Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	now := time.Now()
+	store.Record("/project/main.go", now)
+	store.Record("/project/README.md", now)
+
+	var out bytes.Buffer
+	if err := frecencyCommand(store, []string{"list"}, &out, now); err != nil {
+		t.Fatalf("list returned an error: %v", err)
+	}
+	if !strings.Contains(out.String(), "/project/main.go") || !strings.Contains(out.String(), "/project/README.md") {
+		t.Errorf("list output misses entries: %q", out.String())
+	}
+
+	out.Reset()
+	if err := frecencyCommand(store, []string{"forget", "/project/main.go"}, &out, now); err != nil {
+		t.Fatalf("forget returned an error: %v", err)
+	}
+	if len(store.Entries(now)) != 1 {
+		t.Errorf("Expected 1 entry after forget, got %d", len(store.Entries(now)))
+	}
+
+	if err := frecencyCommand(store, []string{"clear"}, &out, now); err != nil {
+		t.Fatalf("clear returned an error: %v", err)
+	}
+	reloaded, _ := frecency.Load(path)
+	if len(reloaded.Entries(now)) != 0 {
+		t.Error("Expected an empty store after clear")
+	}
+
+	for _, args := range [][]string{{"unknown"}, {"forget"}} {
+		if err := frecencyCommand(store, args, &out, now); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
+
+func TestRunSubcommandUnknown(t *testing.T) {
+	for _, args := range [][]string{nil, {"--post-cmd", "vim"}, {"notacommand"}} {
+		if handled, _ := RunSubcommand(args, &bytes.Buffer{}); handled {
+			t.Errorf("RunSubcommand(%v) should not be handled", args)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+# jetfind key bindings and completion for bash.
+# Add to ~/.bashrc: eval "$(jetfind shell-init bash)"
+#
+#   ctrl-t       insert the selected paths at the cursor
+#   alt-c        change to the selected directory
+#   **<TAB>      complete the word with the selected paths, e.g. vim src/**<TAB>
+#
+# The TUI height can be changed with JETFIND_HEIGHT (default 40%).
+
+__jetfind_select() {
+  jetfind --height "${JETFIND_HEIGHT:-40%}" --layout reverse --quote bash "$@" < /dev/tty
+}
+
+__jetfind_insert() {
+  local selected
+  selected=$(__jetfind_select) || return
+  [[ -n $selected ]] || return
+  READLINE_LINE="${READLINE_LINE:0:READLINE_POINT}$selected ${READLINE_LINE:READLINE_POINT}"
+  READLINE_POINT=$((READLINE_POINT + ${#selected} + 1))
+}
+
+__jetfind_cd() {
+  local selected
+  selected=$(__jetfind_select --dirs) || return
+  [[ -n $selected ]] || return
+  eval "builtin cd -- $selected"
+}
+
+_jetfind_completion() {
+  local cur=${COMP_WORDS[COMP_CWORD]}
+  if [[ $cur != *'**' ]]; then
+    # Leave the other words to bash-completion, when loaded, or to the
+    # default completion.
+    if declare -F _completion_loader > /dev/null; then
+      _completion_loader "$@"
+      return
+    fi
+    return 0
+  fi
+
+  local dir=${cur%'**'} selected
+  if [[ -n $dir && $dir != */ ]]; then
+    dir="$dir/"
+  fi
+  selected=$(eval "builtin cd -- ${dir:-.}" && __jetfind_select)
+  [[ -n $selected ]] || return
+
+  # Each path gets the directory, e.g. src/a\ b src/c.
+  local -a paths
+  eval "paths=($selected)"
+  local path reply=
+  for path in "${paths[@]}"; do
+    printf -v path '%q' "$path"
+    reply+="$dir$path "
+  done
+  COMPREPLY=("${reply% }")
+}
+
+if [[ $- == *i* ]]; then
+  bind -m emacs-standard -x '"\C-t": __jetfind_insert'
+  bind -m vi-insert -x '"\C-t": __jetfind_insert'
+  bind -m emacs-standard -x '"\ec": __jetfind_cd'
+  bind -m vi-insert -x '"\ec": __jetfind_cd'
+  complete -D -F _jetfind_completion -o default -o bashdefault
+fi
@@ -0,0 +1,69 @@
+# jetfind key bindings and completion for fish.
+# Add to ~/.config/fish/config.fish: jetfind shell-init fish | source
+#
+#   ctrl-t       insert the selected paths at the cursor
+#   alt-c        change to the selected directory
+#   **<TAB>      complete the token with the selected paths, e.g. vim src/**<TAB>
+#
+# The TUI height can be changed with JETFIND_HEIGHT (default 40%).
+
+function __jetfind_select
+    set -l height 40%
+    set -q JETFIND_HEIGHT; and set height $JETFIND_HEIGHT
+    jetfind --height $height --layout reverse --quote fish $argv </dev/tty
+end
+
+function jetfind-file-widget
+    set -l selected (__jetfind_select | string collect)
+    if test -n "$selected"
+        commandline -i -- "$selected "
+    end
+    commandline -f repaint
+end
+
+function jetfind-cd-widget
+    set -l selected (__jetfind_select --dirs | string collect)
+    if test -n "$selected"
+        eval "builtin cd -- $selected"
+    end
+    commandline -f repaint
+end
+
+function jetfind-completion
+    set -l token (commandline -ct)
+    if not string match -q -- '*\*\*' $token
+        commandline -f complete
+        return
+    end
+
+    set -l dir (string replace -r '\*\*$' '' -- $token)
+    if test -n "$dir"; and not string match -q -- '*/' $dir
+        set dir "$dir/"
+    end
+
+    # fish has no subshells: go back to the current directory afterwards.
+    set -l here $PWD
+    if test -n "$dir"
+        eval "builtin cd -- $dir"; or return
+    end
+    set -l selected (__jetfind_select | string collect)
+    builtin cd -- $here
+
+    if test -n "$selected"
+        # Each path gets the directory, e.g. src/a\ b src/c.
+        set -l paths (eval "printf '%s\\0' $selected" | string split0)
+        commandline -rt -- (string join ' ' -- $dir(string escape -- $paths))" "
+    end
+    commandline -f repaint
+end
+
+if status is-interactive
+    bind \ct jetfind-file-widget
+    bind \ec jetfind-cd-widget
+    bind \t jetfind-completion
+    if bind -M insert >/dev/null 2>&1
+        bind -M insert \ct jetfind-file-widget
+        bind -M insert \ec jetfind-cd-widget
+        bind -M insert \t jetfind-completion
+    end
+end
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+//go:embed shell
+var shellScripts embed.FS
+
+var shells = []string{"bash", "zsh", "fish"}
+
+const shellInitUsage = "usage: jetfind shell-init bash|zsh|fish"
+
+// runShellInit prints the key bindings and the completion for a shell, to
+// be loaded from its startup file.
+func runShellInit(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(shellInitUsage)
+	}
+	if !slices.Contains(shells, args[0]) {
+		return fmt.Errorf("unsupported shell: %s\n%s", args[0], shellInitUsage)
+	}
+
+	script, err := shellScripts.ReadFile("shell/jetfind." + args[0])
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(script)
+	return err
+}
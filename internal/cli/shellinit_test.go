@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellInitSyntax(t *testing.T) {
+	checks := map[string][]string{
+		"bash": {"bash", "-n"},
+		"zsh":  {"zsh", "-n"},
+		"fish": {"fish", "--no-execute"},
+	}
+
+	for _, shell := range shells {
+		t.Run(shell, func(t *testing.T) {
+			var script bytes.Buffer
+			if err := runShellInit([]string{shell}, &script); err != nil {
+				t.Fatalf("shell-init %s returned an error: %v", shell, err)
+			}
+			if !strings.Contains(script.String(), "jetfind --height") {
+				t.Errorf("shell-init %s does not run jetfind", shell)
+			}
+
+			check := checks[shell]
+			if _, err := exec.LookPath(check[0]); err != nil {
+				t.Skipf("%s is not installed", shell)
+			}
+			path := filepath.Join(t.TempDir(), "jetfind."+shell)
+			if err := os.WriteFile(path, script.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(check[0], append(check[1:], path)...).CombinedOutput(); err != nil {
+				t.Errorf("Invalid %s script: %v\n%s", shell, err, out)
+			}
+		})
+	}
+}
+
+func TestShellInitUnknownShell(t *testing.T) {
+	var out bytes.Buffer
+	for _, args := range [][]string{{}, {"tcsh"}, {"bash", "zsh"}} {
+		if err := runShellInit(args, &out); err == nil {
+			t.Errorf("Expected an error for %v, got none", args)
+		}
+	}
+}
+
+// TestBashWidgets runs the bash widgets with a stubbed selection.
+func TestBashWidgets(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	var script bytes.Buffer
+	if err := runShellInit([]string{"bash"}, &script); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "my dir", "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		commands string
+		expected string
+	}{
+		{
+			name:     "cd",
+			commands: `__jetfind_select() { echo "'my dir'"; }; __jetfind_cd; basename "$PWD"`,
+			expected: "my dir",
+		},
+		{
+			name:     "insert",
+			commands: `__jetfind_select() { echo "'a b' 'c'"; }; READLINE_LINE="vim  -R"; READLINE_POINT=4; __jetfind_insert; echo "$READLINE_LINE|$READLINE_POINT"`,
+			expected: "vim 'a b' 'c'  -R|14",
+		},
+		{
+			name:     "completion",
+			commands: `__jetfind_select() { echo "'x y.go'"; }; COMP_WORDS=(vim "my\ dir/src/**"); COMP_CWORD=1; _jetfind_completion; echo "${COMPREPLY[0]}"`,
+			expected: `my\ dir/src/x\ y.go`,
+		},
+		{
+			name:     "completion of several paths",
+			commands: `__jetfind_select() { echo "'a b' 'c' 'it'\\''s'"; }; COMP_WORDS=(vim "my\ dir/src**"); COMP_CWORD=1; _jetfind_completion; echo "${COMPREPLY[@]}|${#COMPREPLY[@]}"`,
+			expected: `my\ dir/src/a\ b my\ dir/src/c my\ dir/src/it\'s|1`,
+		},
+		{
+			name:     "completion of other words",
+			commands: `COMP_WORDS=(vim src); COMP_CWORD=1; COMPREPLY=(); _jetfind_completion; echo "${#COMPREPLY[@]}"`,
+			expected: "0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command("bash", "-c", script.String()+"\n"+tc.commands)
+			cmd.Dir = root
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash failed: %v\n%s", err, out)
+			}
+			if obtained := strings.TrimSpace(string(out)); obtained != tc.expected {
+				t.Errorf("Expected %q, obtained %q", tc.expected, obtained)
+			}
+		})
+	}
+}
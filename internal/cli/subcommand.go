@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"io"
+)
+
+type subcommand func(args []string, out io.Writer) error
+
+var subcommands = map[string]subcommand{
+	"config":     runConfig,
+	"frecency":   runFrecency,
+	"shell-init": runShellInit,
+}
+
+// RunSubcommand runs args[0] as a subcommand when it names one and reports
+// whether it did.
+func RunSubcommand(args []string, out io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return false, nil
+	}
+	return true, cmd(args[1:], out)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"jetfind/internal/config"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ApproveProject lets a project file set commands and the files jetfind
+// writes once the user approved its current content. It asks on the
+// terminal, and without one ignores those settings with a warning.
+func ApproveProject(path string, data []byte, settings []string) bool {
+	store, err := config.LoadTrustStore(config.GetTrustFilePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring the commands and files of %s: %v\n", path, err)
+		return false
+	}
+	interactive := term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stderr.Fd())
+	return approveProject(store, os.Stdin, os.Stderr, interactive, path, data, settings)
+}
+
+// TrustedProject lets a project file set commands and files only when the user
+// already approved its current content, without asking.
+func TrustedProject(path string, data []byte, settings []string) bool {
+	store, err := config.LoadTrustStore(config.GetTrustFilePath())
+	return err == nil && store.Trusted(path, data)
+}
+
+func approveProject(store *config.TrustStore, in io.Reader, out io.Writer, interactive bool, path string, data []byte, settings []string) bool {
+	if store.Trusted(path, data) {
+		return true
+	}
+	if !interactive {
+		fmt.Fprintf(out, "Warning: ignoring %s of the untrusted %s; run jetfind in a terminal to approve it\n", strings.Join(settings, ", "), path)
+		return false
+	}
+
+	fmt.Fprintf(out, "%s sets commands or files (%s).\nTrust it? [y/N] ", path, strings.Join(settings, ", "))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+	default:
+		fmt.Fprintf(out, "Ignoring %s\n", strings.Join(settings, ", "))
+		return false
+	}
+
+	if err := store.Trust(path, data); err != nil {
+		fmt.Fprintf(out, "Warning: failed to record the approval: %v\n", err)
+	}
+	return true
+}
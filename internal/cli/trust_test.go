@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"jetfind/internal/config"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApproveProject(t *testing.T) {
+	path, data, settings := "/repo/.jetfind.yml", []byte("post_cmd: vim"), []string{"post_cmd"}
+
+	testCases := []struct {
+		name        string
+		interactive bool
+		answer      string
+		expected    bool
+	}{
+		{name: "not interactive", answer: "y\n"},
+		{name: "declined", interactive: true, answer: "\n"},
+		{name: "approved", interactive: true, answer: "Yes\n", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "trusted")
+			store, _ := config.LoadTrustStore(file)
+			var out bytes.Buffer
+			if approved := approveProject(store, strings.NewReader(tc.answer), &out, tc.interactive, path, data, settings); approved != tc.expected {
+				t.Errorf("Expected approval %v, obtained %v", tc.expected, approved)
+			}
+			if !strings.Contains(out.String(), "post_cmd") {
+				t.Errorf("Expected the settings to be named, obtained %q", out.String())
+			}
+
+			store, _ = config.LoadTrustStore(file)
+			if store.Trusted(path, data) != tc.expected {
+				t.Errorf("Expected the approval to be recorded: %v", tc.expected)
+			}
+			if tc.expected && !approveProject(store, strings.NewReader(""), &out, false, path, data, settings) {
+				t.Error("Expected a trusted file to be approved without asking")
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package config
 
 import (
+	_ "embed"
 	"fmt"
+	findingnore "jetfind/internal/findignore"
+	"jetfind/internal/scanengine"
+	"maps"
 	"os"
 	"path/filepath"
-	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/adrg/xdg"
 	"gopkg.in/yaml.v3"
@@ -12,125 +18,260 @@ import (
 
 const APPNAME = "jetfind"
 
+// Template is a commented configuration file holding the defaults.
+//
+//go:embed default.yml
+var Template []byte
+
 var Default *Config = &Config{
+	Version: CurrentVersion,
 	Filter: FilterConfig{
-		Type:       "fuzzy",
-		Algo:       "jarowinkler",
-		Threashold: 0.9,
+		Type:      "fuzzy",
+		Algo:      "jarowinkler",
+		Threshold: 0.9,
+		Tiebreak:  []string{"score", "length", "index"},
+		Case:      "smart",
+		Scope:     "auto",
 	},
 	Findignore: FindIgnoreConfig{
 		Enable:       false,
 		HiddenIgnore: false,
 	},
-	Tui: TuiConfig{
-		HighlightedFile: HighlightedFileConfig{
-			Foreground: "#7DD3FC",
-		},
-		QueryBox: QueryBoxConfig{
-			TextForeground:   "#D1D5DB",
-			TextBackground:   "#1A1B23",
-			BorderForeground: "#6366F1",
-		},
+	History: HistoryConfig{
+		Size: 1000,
+	},
+	Frecency: FrecencyConfig{
+		Enable: false,
+		Weight: 0.3,
 	},
 }
 
 type Config struct {
+	// Version is the schema version the file was written for.
+	Version int `yaml:"version"`
+	// PostCmd runs with the selected paths when --post-cmd is not given.
+	PostCmd string `yaml:"post_cmd"`
+	// Roots are the directories scanned instead of the current one.
+	Roots      []string         `yaml:"roots"`
 	Filter     FilterConfig     `yaml:"filter"`
 	Findignore FindIgnoreConfig `yaml:"findignore"`
+	History    HistoryConfig    `yaml:"history"`
+	Frecency   FrecencyConfig   `yaml:"frecency"`
+	Preview    PreviewConfig    `yaml:"preview"`
 	Tui        TuiConfig        `yaml:"tui"`
+	// Profiles are named sets of settings applied over the configuration
+	// files, see profile for the settings they may hold.
+	Profiles map[string]yaml.Node `yaml:"profiles"`
+
+	origins        map[string]Origin
+	warnings       []string
+	profileSources map[string]string
 }
 
 type FilterConfig struct {
-	Type       string  `yaml:"type"`
-	Algo       string  `yaml:"algorithm"`
-	Threashold float64 `yaml:"threashold"`
+	Type      string   `yaml:"type"`
+	Algo      string   `yaml:"algorithm"`
+	Threshold float64  `yaml:"threshold"`
+	Tiebreak  []string `yaml:"tiebreak"`
+	Case      string   `yaml:"case"`
+	// Scope is the part of the paths matched, see scanengine.Scopes.
+	Scope          string `yaml:"scope"`
+	Normalization  string `yaml:"normalization"`
+	FoldDiacritics bool   `yaml:"fold_diacritics"`
 }
 
 type FindIgnoreConfig struct {
 	Enable       bool `yaml:"enable"`
 	HiddenIgnore bool `yaml:"hidden_ignore"`
+	// Patterns are .findignore lines applied after the file, if enabled.
+	Patterns []string `yaml:"patterns"`
+}
+
+type HistoryConfig struct {
+	File string `yaml:"file"`
+	Size int    `yaml:"size"`
+}
+
+type FrecencyConfig struct {
+	Enable bool    `yaml:"enable"`
+	Weight float64 `yaml:"weight"`
+	File   string  `yaml:"file"`
+}
+
+type PreviewConfig struct {
+	// Command prints the preview of {}, the highlighted path. Empty shows
+	// the beginning of the file.
+	Command string `yaml:"command"`
 }
 
 type TuiConfig struct {
+	// Theme is the preset the colors start from, see Themes. Empty follows
+	// the background of the terminal.
+	Theme           string                `yaml:"theme"`
 	HighlightedFile HighlightedFileConfig `yaml:"highlighted_file"`
 	QueryBox        QueryBoxConfig        `yaml:"query_box"`
+	Colors          ColorsConfig          `yaml:"colors"`
+	// Height is the number of rows used by the TUI, e.g. "20", or a share
+	// of the terminal, e.g. "40%". The TUI is then drawn below the prompt
+	// instead of taking over the screen. Empty means full screen.
+	Height string `yaml:"height"`
+	// Layout "reverse" puts the query box at the bottom.
+	Layout string `yaml:"layout"`
+	// Keys maps key chords, such as "ctrl-y", to actions, such as
+	// "copy-path" or "execute(vim {})".
+	Keys map[string]string `yaml:"keys"`
 }
 
+// HighlightedFileConfig sets the colors of the row under the cursor.
 type HighlightedFileConfig struct {
-	Foreground string `yaml:"foreground"`
-	Background string `yaml:"background"`
+	Foreground Color `yaml:"foreground"`
+	Background Color `yaml:"background"`
 }
 
 type QueryBoxConfig struct {
-	TextForeground   string `yaml:"text_foreground"`
-	TextBackground   string `yaml:"text_background"`
-	BorderForeground string `yaml:"border_foreground"`
+	TextForeground   Color `yaml:"text_foreground"`
+	TextBackground   Color `yaml:"text_background"`
+	BorderForeground Color `yaml:"border_foreground"`
 }
 
-func GetConfigDir() string {
-	return filepath.Join(xdg.ConfigHome, APPNAME)
-}
+const (
+	LayoutDefault = "default"
+	LayoutReverse = "reverse"
+)
 
-func GetConfigFilePath() (string, error) {
-	cfgPath, err := xdg.ConfigFile(filepath.Join(APPNAME, "config.yml"))
-	if err != nil {
-		return "", fmt.Errorf("error while getting the configuration file path")
+// ParseHeight parses a TUI height, either a number of rows or a percentage
+// of the terminal height.
+func ParseHeight(height string) (value int, percent bool, err error) {
+	number, percent := strings.CutSuffix(height, "%")
+	value, err = strconv.Atoi(number)
+	if err != nil || value <= 0 || (percent && value > 100) {
+		return 0, false, fmt.Errorf("invalid height: %s. Must be a number of rows or a percentage", height)
 	}
-	return cfgPath, nil
+	return value, percent, nil
 }
 
-func Load(configPath string) (*Config, error) {
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file does not exist: %s", configPath)
+// ScanFilterOptions returns the filter settings for paths scanned from root,
+// or from the configured roots.
+func (c *Config) ScanFilterOptions(root string) scanengine.FilterOptions {
+	f := c.Filter
+	scope := f.Scope
+	if scope == "" {
+		scope = scanengine.ScopeAuto
+	}
+	return scanengine.FilterOptions{
+		Type:      f.Type,
+		Algo:      f.Algo,
+		Threshold: f.Threshold,
+		Case:      f.Case,
+		Normalizer: scanengine.Normalizer{
+			Form:           f.Normalization,
+			FoldDiacritics: f.FoldDiacritics,
+		},
+		Scope: scanengine.Scope{Mode: scope, Root: root, Roots: c.ScanRoots()},
 	}
+}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+// ScanRoots returns the roots to scan, with a leading ~ expanded, or nil to
+// scan the current directory.
+func (c *Config) ScanRoots() []string {
+	if len(c.Roots) == 0 {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	roots := make([]string, len(c.Roots))
+	for i, root := range c.Roots {
+		if rest, ok := strings.CutPrefix(root, "~"); ok && home != "" && (rest == "" || rest[0] == '/') {
+			root = home + rest
+		}
+		roots[i] = root
 	}
+	return roots
+}
+
+func GetConfigDir() string {
+	return filepath.Join(xdg.ConfigHome, APPNAME)
+}
+
+func GetStateDir() string {
+	return filepath.Join(xdg.StateHome, APPNAME)
+}
 
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+// GetHistoryFilePath returns the configured history file, or the default
+// one in the state directory.
+func (h HistoryConfig) GetHistoryFilePath() string {
+	if h.File != "" {
+		return h.File
 	}
+	return filepath.Join(GetStateDir(), "history")
+}
 
-	if err := config.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
+// GetFrecencyFilePath returns the configured frecency database, or the
+// default one in the state directory.
+func (f FrecencyConfig) GetFrecencyFilePath() string {
+	if f.File != "" {
+		return f.File
 	}
+	return filepath.Join(GetStateDir(), "frecency.json")
+}
 
-	return &config, nil
+// Ranking returns the ranking for the filter engine. boost is blended with
+// the filter scores only when frecency is enabled.
+func (c *Config) Ranking(boost func(path string) float64) scanengine.Ranking {
+	ranking := scanengine.Ranking{Order: c.Filter.Tiebreak}
+	if c.Frecency.Enable && boost != nil {
+		ranking.Boost = boost
+		ranking.BoostWeight = c.Frecency.Weight
+	}
+	return ranking
 }
 
-func LoadOrDefault() *Config {
-	var cfg *Config
+func GetFindIgnorePath() string {
+	return filepath.Join(GetConfigDir(), ".findignore")
+}
 
-	cfgPath, err := GetConfigFilePath()
-	if err != nil {
-		cfg = Default
-	} else {
-		cfg, err = Load(cfgPath)
+// FindIgnore returns the rules of the .findignore file, when enabled,
+// followed by the configured patterns, or nil when there are none.
+func (f FindIgnoreConfig) FindIgnore() (*findingnore.FindIgnore, error) {
+	var lines []string
+	if f.Enable {
+		data, err := os.ReadFile(GetFindIgnorePath())
 		if err != nil {
-			cfg = Default
+			return nil, err
 		}
+		lines = strings.Split(string(data), "\n")
 	}
-
-	if reflect.DeepEqual(cfg.Tui, TuiConfig{}) {
-		cfg.Tui = Default.Tui
-	} else {
-		if reflect.DeepEqual(cfg.Tui.HighlightedFile, HighlightedFileConfig{}) {
-			cfg.Tui.HighlightedFile = Default.Tui.HighlightedFile
-		}
-		if reflect.DeepEqual(cfg.Tui.QueryBox, QueryBoxConfig{}) {
-			cfg.Tui.QueryBox = Default.Tui.QueryBox
-		}
+	if !f.Enable && len(f.Patterns) == 0 {
+		return nil, nil
 	}
+	return findingnore.Parse(append(lines, f.Patterns...), f.Enable || f.HiddenIgnore)
+}
 
-	if reflect.DeepEqual(cfg.Filter, FilterConfig{}) {
-		cfg.Filter = Default.Filter
+func GetConfigFilePath() (string, error) {
+	cfgPath, err := xdg.ConfigFile(filepath.Join(APPNAME, "config.yml"))
+	if err != nil {
+		return "", fmt.Errorf("error while getting the configuration file path")
 	}
+	return cfgPath, nil
+}
 
-	if reflect.DeepEqual(cfg.Findignore, FindIgnoreConfig{}) {
-		cfg.Findignore = Default.Findignore
+// Load merges the configuration file at configPath over the defaults.
+func Load(configPath string) (*Config, error) {
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("config file does not exist: %s", configPath)
 	}
-	return cfg
+	return LoadLayers([]FileLayer{{Layer: LayerUser, Path: configPath}}, nil, "", nil)
+}
+
+// defaultCopy returns a copy of Default that callers can override freely.
+func defaultCopy() *Config {
+	cfg := *Default
+	cfg.Filter.Tiebreak = append([]string(nil), Default.Filter.Tiebreak...)
+	cfg.Findignore.Patterns = slices.Clone(Default.Findignore.Patterns)
+	cfg.Tui.Keys = maps.Clone(Default.Tui.Keys)
+	cfg.Roots = slices.Clone(Default.Roots)
+	cfg.Profiles = maps.Clone(Default.Profiles)
+	cfg.origins = nil
+	cfg.warnings = nil
+	cfg.profileSources = nil
+	return &cfg
 }
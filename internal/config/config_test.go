@@ -3,14 +3,17 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestLoadValidConfig(t *testing.T) {
 	validYAML := `filter:
   type: "fuzzy"
   algorithm: "jarowinkler"
-  threashold: 0.8
+  threshold: 0.8
 findignore:
   enable: false
   hidden_ignore: true
@@ -34,11 +37,11 @@ tui:
 	if cfg.Filter.Type != "fuzzy" {
 		t.Errorf("Expected filter type 'fuzzy', got '%s'", cfg.Filter.Type)
 	}
-	if cfg.Filter.Threashold != 0.8 {
-		t.Errorf("Expected threshold 0.8, got %f", cfg.Filter.Threashold)
+	if cfg.Filter.Threshold != 0.8 {
+		t.Errorf("Expected threshold 0.8, got %f", cfg.Filter.Threshold)
 	}
-	if cfg.Tui.HighlightedFile.Foreground != "#FFFFFF" {
-		t.Errorf("Expected foreground '#FFFFFF', got '%s'", cfg.Tui.HighlightedFile.Foreground)
+	if cfg.Tui.HighlightedFile.Foreground != NewColor("#FFFFFF") {
+		t.Errorf("Expected foreground '#FFFFFF', got '%v'", cfg.Tui.HighlightedFile.Foreground)
 	}
 }
 
@@ -52,14 +55,14 @@ func TestLoadInvalidConfig(t *testing.T) {
 			yaml: `filter:
   type: "invalid"
   algorithm: "jarowinkler"
-  threashold: 0.8`,
+  threshold: 0.8`,
 		},
 		{
 			name: "invalid threshold",
 			yaml: `filter:
   type: "fuzzy"
   algorithm: "jarowinkler"
-  threashold: 1.5`,
+  threshold: 1.5`,
 		},
 		{
 			name: "invalid color",
@@ -99,3 +102,43 @@ func createTempConfig(t *testing.T, content string) string {
 
 	return tmpFile
 }
+
+func TestParseHeight(t *testing.T) {
+	tests := []struct {
+		height  string
+		value   int
+		percent bool
+		wantErr bool
+	}{
+		{height: "20", value: 20},
+		{height: "40%", value: 40, percent: true},
+		{height: "100%", value: 100, percent: true},
+		{height: "0", wantErr: true},
+		{height: "-3", wantErr: true},
+		{height: "101%", wantErr: true},
+		{height: "tall", wantErr: true},
+		{height: "%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.height, func(t *testing.T) {
+			value, percent, err := ParseHeight(tt.height)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHeight(%q) error = %v, wantErr %v", tt.height, err, tt.wantErr)
+			}
+			if value != tt.value || percent != tt.percent {
+				t.Errorf("ParseHeight(%q) = %d, %v, want %d, %v", tt.height, value, percent, tt.value, tt.percent)
+			}
+		})
+	}
+}
+
+func TestTemplateHoldsTheDefaults(t *testing.T) {
+	var cfg Config
+	if err := yaml.Unmarshal(Template, &cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(&cfg, Default) {
+		t.Errorf("Expected the template to hold\n%+v\nobtained\n%+v", *Default, cfg)
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError is a problem with the setting at Path, e.g. "filter.type".
+// Source is the file or environment variable that set it, and Line and
+// Column its position in the file, when known.
+type FieldError struct {
+	Path    string
+	Source  string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	var b strings.Builder
+	if e.Source != "" {
+		b.WriteString(e.Source)
+		if e.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", e.Line, e.Column)
+		}
+		b.WriteString(": ")
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path + ": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// Errors lists all the problems of a configuration, one per line.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// err returns e as an error, nil when it is empty.
+func (e Errors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// locate sets the source and the position of the errors from the origins
+// of their settings.
+func (e Errors) locate(c *Config) {
+	for _, err := range e {
+		if err.Source != "" {
+			continue
+		}
+		if o, ok := c.origins[err.Path]; ok {
+			err.Source, err.Line, err.Column = o.Source, o.Line, o.Column
+		}
+	}
+}
+
+// unknownKeys reports the keys of a YAML mapping that do not match a
+// field of t, a struct with yaml tags.
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix, source string) Errors {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var errs Errors
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		field, ok := fieldByTag(t, key.Value)
+		if !ok {
+			errs = append(errs, &FieldError{Path: prefix + key.Value, Source: source, Line: key.Line, Column: key.Column, Message: "unknown key"})
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			errs = append(errs, unknownKeys(value, field.Type, prefix+key.Value+".", source)...)
+		}
+	}
+	return errs
+}
+
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); tag == name {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+var typeErrorLine = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// typeErrors turns the messages of a yaml.TypeError, such as "line 3:
+// cannot unmarshal !!str `many` into int", into errors at the settings of
+// root on those lines.
+func typeErrors(err *yaml.TypeError, root *yaml.Node, source string) Errors {
+	var errs Errors
+	for _, msg := range err.Errors {
+		fe := &FieldError{Source: source, Message: msg}
+		if m := typeErrorLine.FindStringSubmatch(msg); m != nil {
+			fe.Line, _ = strconv.Atoi(m[1])
+			fe.Message = m[2]
+			fe.Path, fe.Column = settingAt(root, "", fe.Line)
+		}
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// settingAt returns the path and the column of the value on line in a YAML
+// mapping.
+func settingAt(node *yaml.Node, prefix string, line int) (string, int) {
+	if node.Kind != yaml.MappingNode {
+		return "", 0
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind == yaml.MappingNode {
+			if path, column := settingAt(value, prefix+key.Value+".", line); path != "" {
+				return path, column
+			}
+		}
+		if value.Line == line {
+			return prefix + key.Value, value.Column
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,353 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, from the lowest precedence to the highest.
+const (
+	LayerDefault = "default"
+	LayerSystem  = "system"
+	LayerUser    = "user"
+	LayerProject = "project"
+	LayerProfile = "profile"
+	LayerEnv     = "env"
+	LayerFlag    = "flag"
+)
+
+// EnvPrefix starts the environment variables that override settings, e.g.
+// JETFIND_FILTER_TYPE for filter.type.
+const EnvPrefix = "JETFIND_"
+
+// ProjectConfigFile is the name of the per-project configuration file.
+const ProjectConfigFile = ".jetfind.yml"
+
+// Origin tells which layer set a value, and the file, environment variable
+// or flag it was read from. Line and Column locate the value in a file.
+type Origin struct {
+	Layer  string
+	Source string
+	Line   int
+	Column int
+}
+
+func (o Origin) String() string {
+	switch {
+	case o.Source == "":
+		return o.Layer
+	case o.Line > 0:
+		return fmt.Sprintf("%s (%s:%d:%d)", o.Layer, o.Source, o.Line, o.Column)
+	}
+	return o.Layer + " (" + o.Source + ")"
+}
+
+// FileLayer is a configuration file merged as part of a layer.
+type FileLayer struct {
+	Layer string
+	Path  string
+}
+
+// Origin returns where the setting at path, e.g. "filter.type", comes from.
+func (c *Config) Origin(path string) Origin {
+	if o, ok := c.origins[path]; ok {
+		return o
+	}
+	return Origin{Layer: LayerDefault}
+}
+
+// SetOrigin records where the setting at path comes from.
+func (c *Config) SetOrigin(path string, o Origin) {
+	if c.origins == nil {
+		c.origins = make(map[string]Origin)
+	}
+	c.origins[path] = o
+}
+
+// EnvName returns the environment variable overriding the setting at path.
+func EnvName(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(path))
+}
+
+// Layers returns the configuration files merged by LoadLayered, from the
+// lowest precedence to the highest. They may not exist.
+func Layers() []FileLayer {
+	var layers []FileLayer
+	for _, dir := range slices.Backward(xdg.ConfigDirs) {
+		layers = append(layers, FileLayer{Layer: LayerSystem, Path: filepath.Join(dir, APPNAME, "config.yml")})
+	}
+	if path, err := GetConfigFilePath(); err == nil {
+		layers = append(layers, FileLayer{Layer: LayerUser, Path: path})
+	}
+	if path := FindProjectConfig("."); path != "" {
+		layers = append(layers, FileLayer{Layer: LayerProject, Path: path})
+	}
+	return layers
+}
+
+// WatchedFiles returns the files the configuration is read from: the
+// layers, which may not exist, and the .findignore file.
+func WatchedFiles() []string {
+	var files []string
+	for _, f := range Layers() {
+		files = append(files, f.Path)
+	}
+	return append(files, GetFindIgnorePath())
+}
+
+// LoadLayered merges the configuration files, the named profile and the
+// environment over the defaults. approve is asked before a project file
+// sets commands.
+func LoadLayered(profile string, approve Approver) (*Config, error) {
+	return LoadLayers(Layers(), os.Environ(), profile, approve)
+}
+
+// LoadLayers merges files, then the named profile, then the JETFIND_*
+// variables of environ, over the defaults, one setting at a time, and
+// validates the result. Missing files are skipped. The profile named by
+// JETFIND_PROFILE is used when profile is empty. The settings of project
+// files that run commands are ignored unless approve, which may be nil,
+// accepts them.
+//
+// Unknown keys, values of the wrong type and invalid values are all
+// reported together as Errors.
+func LoadLayers(files []FileLayer, environ []string, profile string, approve Approver) (*Config, error) {
+	cfg := defaultCopy()
+	var errs Errors
+	for _, f := range files {
+		fileErrs, err := cfg.mergeFile(f, approve)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, fileErrs...)
+	}
+	source := "--profile"
+	if profile == "" {
+		for _, kv := range environ {
+			if value, ok := strings.CutPrefix(kv, ProfileEnv+"="); ok {
+				profile, source = value, ProfileEnv
+			}
+		}
+	}
+	if profile != "" {
+		errs = append(errs, cfg.applyProfile(profile, source)...)
+	}
+	errs = append(errs, cfg.mergeEnv(environ)...)
+
+	invalid := cfg.validate()
+	invalid.locate(cfg)
+	errs = append(errs, invalid...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return cfg, nil
+}
+
+// mergeFile merges the settings of a file. It returns the problems of the
+// settings, and an error when the file cannot be read at all.
+func (c *Config) mergeFile(f FileLayer, approve Approver) (Errors, error) {
+	data, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML in %s: %w", f.Path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	dropNulls(root)
+	warnings, _, errs := migrate(root, f.Path)
+	if errs != nil {
+		return errs, nil
+	}
+	c.warnings = append(c.warnings, warnings...)
+	profileErrs := checkProfiles(root, f.Path)
+	if f.Layer == LayerProject {
+		if settings := sensitiveSettings(root); len(settings) > 0 && (approve == nil || !approve(f.Path, data, settings)) {
+			for _, path := range settings {
+				remove(root, path)
+			}
+		}
+	}
+	errs = unknownKeys(root, reflect.TypeOf(*c), "", f.Path)
+	errs = append(errs, profileErrs...)
+	// Decoding over the current values only replaces the settings present
+	// in the file. The values of the wrong type are skipped.
+	var typeErr *yaml.TypeError
+	if err := root.Decode(c); errors.As(err, &typeErr) {
+		errs = append(errs, typeErrors(typeErr, root, f.Path)...)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML in %s: %w", f.Path, err)
+	}
+
+	eachSetting(reflect.ValueOf(c).Elem(), "", func(path string, _ reflect.Value) error {
+		if value := lookup(root, path); value != nil {
+			c.SetOrigin(path, Origin{Layer: f.Layer, Source: f.Path, Line: value.Line, Column: value.Column})
+		}
+		return nil
+	})
+	if profiles := lookup(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		if c.profileSources == nil {
+			c.profileSources = make(map[string]string)
+		}
+		for i := 0; i < len(profiles.Content); i += 2 {
+			c.profileSources[profiles.Content[i].Value] = f.Path
+		}
+	}
+	return errs, nil
+}
+
+// mergeEnv applies the JETFIND_* variables of environ and returns the
+// problems of their values. Lists are comma separated; maps cannot be set
+// from the environment.
+func (c *Config) mergeEnv(environ []string) Errors {
+	vars := make(map[string]string)
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(name, EnvPrefix) {
+			vars[name] = value
+		}
+	}
+	for _, a := range aliases {
+		if value, ok := vars[EnvName(a.old)]; ok {
+			if _, ok := vars[EnvName(a.new)]; !ok {
+				vars[EnvName(a.new)] = value
+			}
+			c.warnings = append(c.warnings, fmt.Sprintf("%s is deprecated since version %d, use %s", EnvName(a.old), a.version, EnvName(a.new)))
+		}
+	}
+
+	var errs Errors
+	eachSetting(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) error {
+		name := EnvName(path)
+		value := vars[name]
+		if value == "" || v.Kind() == reflect.Map {
+			return nil
+		}
+
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+		if v.Kind() == reflect.Slice {
+			node = &yaml.Node{Kind: yaml.SequenceNode}
+			for _, item := range strings.Split(value, ",") {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: strings.TrimSpace(item)})
+			}
+		}
+		if err := node.Decode(v.Addr().Interface()); err != nil {
+			errs = append(errs, &FieldError{Path: path, Source: name, Message: strings.TrimPrefix(err.Error(), "yaml: unmarshal errors:\n  line 0: ")})
+			return nil
+		}
+		c.SetOrigin(path, Origin{Layer: LayerEnv, Source: name})
+		return nil
+	})
+	return errs
+}
+
+// Annotated returns c in YAML, with the origin of every setting in a
+// comment.
+func (c *Config) Annotated() ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(c); err != nil {
+		return nil, err
+	}
+	eachSetting(reflect.ValueOf(c).Elem(), "", func(path string, _ reflect.Value) error {
+		key, value := find(&root, path)
+		if key == nil {
+			return nil
+		}
+		// The comment of the key would end up after a flow collection.
+		commented := key
+		if value.Kind == yaml.SequenceNode {
+			value.Style = yaml.FlowStyle
+			commented = value
+		}
+		if value.Kind == yaml.MappingNode && len(value.Content) == 0 {
+			commented = value
+		}
+		commented.LineComment = c.Origin(path).String()
+		return nil
+	})
+	return yaml.Marshal(&root)
+}
+
+// eachSetting calls fn with the path and value of every setting below v,
+// a struct with yaml tags.
+func eachSetting(v reflect.Value, prefix string, fn func(path string, v reflect.Value) error) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		field := v.Field(i)
+		var err error
+		// A color is a single setting, whether set with one value or two.
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(Color{}) {
+			err = eachSetting(field, path+".", fn)
+		} else {
+			err = fn(path, field)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the value at path in a YAML mapping, or nil.
+func lookup(node *yaml.Node, path string) *yaml.Node {
+	_, value := find(node, path)
+	return value
+}
+
+// find returns the key and the value at path in a YAML mapping, or nils.
+func find(node *yaml.Node, path string) (key, value *yaml.Node) {
+	for _, name := range strings.Split(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		key, value = nil, nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				key, value = node.Content[i], node.Content[i+1]
+			}
+		}
+		if value == nil {
+			return nil, nil
+		}
+		node = value
+	}
+	return key, value
+}
+
+// dropNulls removes the empty values from the mappings in node, so that a
+// key without a value, such as a bare "tui:", keeps the lower layers.
+func dropNulls(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		value := node.Content[i+1]
+		if value.Kind == yaml.ScalarNode && value.ShortTag() == "!!null" {
+			continue
+		}
+		dropNulls(value)
+		content = append(content, node.Content[i], value)
+	}
+	node.Content = content
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeLayer(t *testing.T, layer, content string) FileLayer {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s config: %v", layer, err)
+	}
+	return FileLayer{Layer: layer, Path: path}
+}
+
+func TestLoadLayers(t *testing.T) {
+	system := writeLayer(t, LayerSystem, "filter:\n  algorithm: ngram\n  threshold: 0.5\n")
+	user := writeLayer(t, LayerUser, "filter:\n  threshold: 0.7\ntui:\n  keys:\n    ctrl-o: execute(vim {})\n")
+	project := writeLayer(t, LayerProject, "filter:\n  tiebreak: [path]\ntui:\n")
+	missing := FileLayer{Layer: LayerProject, Path: filepath.Join(t.TempDir(), ProjectConfigFile)}
+	environ := []string{"JETFIND_FILTER_TYPE=contains", "JETFIND_HISTORY_SIZE=", "JETFIND_FILTER_TIEBREAK=length, path", "PATH=/bin"}
+
+	cfg, err := LoadLayers([]FileLayer{system, user, project, missing}, environ, "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Filter.Type != "contains" || cfg.Filter.Algo != "ngram" || cfg.Filter.Threshold != 0.7 {
+		t.Errorf("Expected contains, ngram and 0.7, obtained %s, %s and %.2f", cfg.Filter.Type, cfg.Filter.Algo, cfg.Filter.Threshold)
+	}
+	if !reflect.DeepEqual(cfg.Filter.Tiebreak, []string{"length", "path"}) {
+		t.Errorf("Expected tiebreak [length path], obtained %v", cfg.Filter.Tiebreak)
+	}
+	if cfg.Filter.Case != Default.Filter.Case || cfg.History.Size != Default.History.Size {
+		t.Errorf("Expected the settings left unset to keep their defaults, obtained %+v", cfg)
+	}
+	if cfg.Tui.Keys["ctrl-o"] != "execute(vim {})" || cfg.Tui.QueryBox != Default.Tui.QueryBox {
+		t.Errorf("Expected the bare tui section to keep the lower layers, obtained %+v", cfg.Tui)
+	}
+
+	testCases := []struct {
+		path     string
+		expected Origin
+	}{
+		{path: "filter.type", expected: Origin{Layer: LayerEnv, Source: "JETFIND_FILTER_TYPE"}},
+		{path: "filter.algorithm", expected: Origin{Layer: LayerSystem, Source: system.Path, Line: 2, Column: 14}},
+		{path: "filter.threshold", expected: Origin{Layer: LayerUser, Source: user.Path, Line: 2, Column: 14}},
+		{path: "filter.tiebreak", expected: Origin{Layer: LayerEnv, Source: "JETFIND_FILTER_TIEBREAK"}},
+		{path: "tui.keys", expected: Origin{Layer: LayerUser, Source: user.Path, Line: 5, Column: 5}},
+		{path: "history.size", expected: Origin{Layer: LayerDefault}},
+	}
+	for _, tc := range testCases {
+		if obtained := cfg.Origin(tc.path); obtained != tc.expected {
+			t.Errorf("Expected %s to come from %v, obtained %v", tc.path, tc.expected, obtained)
+		}
+	}
+}
+
+func TestLoadLayersErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    []FileLayer
+		environ  []string
+		expected string
+	}{
+		{name: "broken YAML", files: []FileLayer{writeLayer(t, LayerUser, "filter: [type")}, expected: "failed to parse YAML"},
+		{name: "wrong type", files: []FileLayer{writeLayer(t, LayerUser, "history:\n  size: many\n")}, expected: "config.yml:2:9: history.size: cannot unmarshal !!str `many` into int"},
+		{name: "invalid value", files: []FileLayer{writeLayer(t, LayerUser, "filter:\n  type: regex\n")}, expected: "invalid filter type"},
+		{name: "invalid variable", environ: []string{"JETFIND_FRECENCY_WEIGHT=heavy"}, expected: "JETFIND_FRECENCY_WEIGHT"},
+		{name: "invalid variable value", environ: []string{"JETFIND_FRECENCY_WEIGHT=2"}, expected: "invalid frecency weight"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadLayers(tc.files, tc.environ, "", nil)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected an error about %q, obtained %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestAnnotated(t *testing.T) {
+	user := writeLayer(t, LayerUser, "filter:\n  tiebreak: [path]\n")
+	cfg, err := LoadLayers([]FileLayer{user}, []string{"JETFIND_FILTER_TYPE=contains"}, "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := cfg.Annotated()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"type: contains # env (JETFIND_FILTER_TYPE)\n",
+		"tiebreak: [path] # user (" + user.Path + ":2:13)\n",
+		"size: 1000 # default\n",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %q in\n%s", expected, data)
+		}
+	}
+}
+
+func TestLoadLayersReportsAllErrors(t *testing.T) {
+	user := writeLayer(t, LayerUser, "filter:\n  type: regex\n  treshold: 0.5\nhistory:\n  size: many\ntui:\n  query_box:\n    border_foreground: \"#zzz\"\n  colour: red\n")
+	project := writeLayer(t, LayerProject, "frecency:\n  weight: 2\n")
+
+	_, err := LoadLayers([]FileLayer{user, project}, []string{"JETFIND_HISTORY_SIZE=lots"}, "", nil)
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("Expected Errors, obtained %v", err)
+	}
+
+	expected := Errors{
+		{Path: "filter.treshold", Source: user.Path, Line: 3, Column: 3, Message: "unknown key"},
+		{Path: "tui.colour", Source: user.Path, Line: 9, Column: 3, Message: "unknown key"},
+		{Path: "history.size", Source: user.Path, Line: 5, Column: 9, Message: "cannot unmarshal !!str `many` into int"},
+		{Path: "history.size", Source: "JETFIND_HISTORY_SIZE", Message: "cannot unmarshal !!str `lots` into int"},
+		{Path: "filter.type", Source: user.Path, Line: 2, Column: 9, Message: "invalid filter type: regex. Must be one of: [fuzzy contains]"},
+		{Path: "frecency.weight", Source: project.Path, Line: 2, Column: 11, Message: "invalid frecency weight: 2.00. Must be in the [0, 1] interval"},
+		{Path: "tui.query_box.border_foreground", Source: user.Path, Line: 8, Column: 24, Message: "invalid color: #zzz. Must be a hex color, an ANSI-256 index or an ANSI color name"},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("Expected\n%v\nobtained\n%v", expected, errs)
+	}
+}
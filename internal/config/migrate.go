@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the version of the configuration schema. Files without
+// a version are version 1.
+const CurrentVersion = 2
+
+// aliases maps the old paths of renamed settings to the current ones. The
+// old names are still accepted, with a deprecation warning.
+var aliases = []struct {
+	old, new string
+	version  int
+}{
+	{old: "filter.threashold", new: "filter.threshold", version: 2},
+}
+
+// Warnings returns the deprecation warnings of the loaded configuration.
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// migrate upgrades a configuration file read from source to the current
+// version, in place. It returns the deprecation warnings and whether root
+// changed.
+func migrate(root *yaml.Node, source string) (warnings []string, changed bool, errs Errors) {
+	version := 1
+	if value := lookup(root, "version"); value != nil {
+		v, err := strconv.Atoi(value.Value)
+		switch {
+		case err != nil || v < 1:
+			return nil, false, Errors{{Path: "version", Source: source, Line: value.Line, Column: value.Column, Message: "invalid version: " + value.Value}}
+		case v > CurrentVersion:
+			return nil, false, Errors{{Path: "version", Source: source, Line: value.Line, Column: value.Column,
+				Message: fmt.Sprintf("version %d is newer than this jetfind, which supports up to %d", v, CurrentVersion)}}
+		}
+		version = v
+	}
+
+	warnings, changed = renameAliases(root, "", source)
+	if profiles := lookup(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			w, c := renameAliases(profiles.Content[i+1], "profiles."+profiles.Content[i].Value+".", source)
+			warnings, changed = append(warnings, w...), changed || c
+		}
+	}
+
+	if version < CurrentVersion {
+		changed = true
+		setVersion(root)
+	}
+	return warnings, changed, nil
+}
+
+// renameAliases renames the old keys of node, whose settings are below
+// prefix, to the current ones.
+func renameAliases(node *yaml.Node, prefix, source string) (warnings []string, changed bool) {
+	for _, a := range aliases {
+		key, _ := find(node, a.old)
+		if key == nil {
+			continue
+		}
+		changed = true
+		if newKey, _ := find(node, a.new); newKey != nil {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: %s is ignored in favor of %s", source, key.Line, prefix+a.old, prefix+a.new))
+			remove(node, a.old)
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("%s:%d: %s is deprecated since version %d, use %s (jetfind config migrate updates the file)", source, key.Line, prefix+a.old, a.version, prefix+a.new))
+		key.Value = a.new[strings.LastIndex(a.new, ".")+1:]
+	}
+	return warnings, changed
+}
+
+// setVersion sets the version of a configuration file to the current one,
+// adding it first when missing.
+func setVersion(root *yaml.Node) {
+	value := strconv.Itoa(CurrentVersion)
+	if node := lookup(root, "version"); node != nil {
+		node.Value = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	// The comment heading the file stays first.
+	if len(root.Content) > 0 {
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, {Kind: yaml.ScalarNode, Tag: "!!int", Value: value}}, root.Content...)
+}
+
+// Migrate upgrades a configuration file to the current version, keeping its
+// comments. It returns the new content, nil when data is up to date, and
+// the deprecation warnings.
+func Migrate(data []byte, source string) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML in %s: %w", source, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, nil
+	}
+
+	warnings, changed, errs := migrate(doc.Content[0], source)
+	if errs != nil {
+		return nil, nil, errs
+	}
+	if !changed {
+		return nil, nil, nil
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	return b.Bytes(), warnings, nil
+}
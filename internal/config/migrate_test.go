@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected string
+		warning  string
+		wantErr  string
+	}{
+		{
+			name:     "renamed key",
+			data:     "# mine\nfilter:\n  threashold: 0.8 # strict\n",
+			expected: "# mine\nversion: 2\nfilter:\n  threshold: 0.8 # strict\n",
+			warning:  "filter.threashold is deprecated since version 2, use filter.threshold",
+		},
+		{
+			name:     "old and new keys",
+			data:     "version: 2\nfilter:\n  threshold: 0.8\n  threashold: 0.5\n",
+			expected: "version: 2\nfilter:\n  threshold: 0.8\n",
+			warning:  "filter.threashold is ignored in favor of filter.threshold",
+		},
+		{
+			name:     "missing version",
+			data:     "filter:\n  type: contains\n",
+			expected: "version: 2\nfilter:\n  type: contains\n",
+		},
+		{name: "up to date", data: "version: 2\nfilter:\n  threshold: 0.8\n"},
+		{name: "empty", data: ""},
+		{name: "newer version", data: "version: 3\n", wantErr: "version 3 is newer than this jetfind"},
+		{name: "invalid version", data: "version: two\n", wantErr: "invalid version: two"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			migrated, warnings, err := Migrate([]byte(tc.data), "config.yml")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("Expected an error about %q, obtained %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(migrated) != tc.expected {
+				t.Errorf("Expected %q, obtained %q", tc.expected, migrated)
+			}
+			if tc.warning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tc.warning)) {
+				t.Errorf("Expected a warning about %q, obtained %q", tc.warning, warnings)
+			}
+		})
+	}
+}
+
+func TestLoadDeprecatedKeys(t *testing.T) {
+	user := writeLayer(t, LayerUser, "filter:\n  threashold: 0.7\n")
+	cfg, err := LoadLayers([]FileLayer{user}, []string{"JETFIND_FILTER_THREASHOLD=0.6"}, "", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Filter.Threshold != 0.6 {
+		t.Errorf("Expected threshold 0.6, obtained %.2f", cfg.Filter.Threshold)
+	}
+	if origin := cfg.Origin("filter.threshold"); origin.Layer != LayerEnv {
+		t.Errorf("Expected the threshold to come from the environment, obtained %v", origin)
+	}
+	if len(cfg.Warnings()) != 2 {
+		t.Errorf("Expected 2 deprecation warnings, obtained %q", cfg.Warnings())
+	}
+
+	newer := writeLayer(t, LayerUser, "version: 9\nfilter:\n  type: contains\n")
+	if _, err := LoadLayers([]FileLayer{newer}, nil, "", nil); err == nil {
+		t.Error("Expected an error for a newer version, got none")
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv selects a profile when --profile is not given.
+const ProfileEnv = EnvPrefix + "PROFILE"
+
+// profile holds the settings a profile may override. A profile defined in
+// several files is taken whole from the one with the highest precedence.
+type profile struct {
+	Filter     FilterConfig     `yaml:"filter"`
+	Findignore FindIgnoreConfig `yaml:"findignore"`
+	Roots      []string         `yaml:"roots"`
+	Preview    PreviewConfig    `yaml:"preview"`
+	Tui        struct {
+		Theme           string                `yaml:"theme"`
+		HighlightedFile HighlightedFileConfig `yaml:"highlighted_file"`
+		QueryBox        QueryBoxConfig        `yaml:"query_box"`
+		Colors          ColorsConfig          `yaml:"colors"`
+	} `yaml:"tui"`
+}
+
+// ProfileNames returns the names of the defined profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.Profiles))
+}
+
+// checkProfiles reports the profiles of a file that are not mappings, or
+// hold settings a profile cannot override, and removes the ones whose
+// names cannot be part of a setting path.
+func checkProfiles(root *yaml.Node, source string) Errors {
+	profiles := lookup(root, "profiles")
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return nil
+	}
+	var errs Errors
+	content := profiles.Content[:0]
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		key, value := profiles.Content[i], profiles.Content[i+1]
+		path := "profiles." + key.Value
+		switch {
+		case key.Value == "" || strings.Contains(key.Value, "."):
+			errs = append(errs, &FieldError{Path: path, Source: source, Line: key.Line, Column: key.Column, Message: "invalid profile name"})
+			continue
+		case value.Kind != yaml.MappingNode:
+			errs = append(errs, &FieldError{Path: path, Source: source, Line: value.Line, Column: value.Column, Message: "a profile must be a mapping"})
+		default:
+			errs = append(errs, unknownKeys(value, reflect.TypeOf(profile{}), path+".", source)...)
+		}
+		content = append(content, key, value)
+	}
+	profiles.Content = content
+	return errs
+}
+
+// applyProfile merges the settings of the named profile. source tells
+// where the name comes from.
+func (c *Config) applyProfile(name, source string) Errors {
+	node, ok := c.Profiles[name]
+	if !ok {
+		message := "no profile named " + name
+		if names := c.ProfileNames(); len(names) > 0 {
+			message += fmt.Sprintf(". Must be one of: %v", names)
+		}
+		return Errors{{Path: "profiles", Source: source, Message: message}}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	prefix := "profiles." + name + "."
+	file := c.profileSources[name]
+	var errs Errors
+	var typeErr *yaml.TypeError
+	if err := node.Decode(c); errors.As(err, &typeErr) {
+		for _, err := range typeErrors(typeErr, &node, file) {
+			if err.Path != "" {
+				err.Path = prefix + err.Path
+			}
+			errs = append(errs, err)
+		}
+	} else if err != nil {
+		return Errors{{Path: prefix[:len(prefix)-1], Source: file, Message: err.Error()}}
+	}
+
+	eachSetting(reflect.ValueOf(c).Elem(), "", func(path string, _ reflect.Value) error {
+		if value := lookup(&node, path); value != nil {
+			c.SetOrigin(path, Origin{Layer: LayerProfile, Source: file, Line: value.Line, Column: value.Column})
+		}
+		return nil
+	})
+	return errs
+}
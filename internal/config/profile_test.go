@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfile(t *testing.T) {
+	root := t.TempDir()
+	user := writeLayer(t, LayerUser, "filter:\n  type: fuzzy\n  algorithm: ngram\nprofiles:\n  docs:\n    roots: ["+root+"]\n    filter:\n      type: contains\n  code:\n    filter:\n      threshold: 0.5\n")
+	project := writeLayer(t, LayerProject, "profiles:\n  code:\n    preview:\n      command: cat {}\n    tui:\n      highlighted_file:\n        foreground: \"#FFFFFF\"\n")
+
+	testCases := []struct {
+		name      string
+		profile   string
+		environ   []string
+		roots     []string
+		filter    string
+		threshold float64
+	}{
+		{name: "none", filter: "fuzzy", threshold: 0.9},
+		{name: "flag", profile: "docs", roots: []string{root}, filter: "contains", threshold: 0.9},
+		{name: "env", environ: []string{"JETFIND_PROFILE=docs"}, roots: []string{root}, filter: "contains", threshold: 0.9},
+		{name: "flag over env", profile: "docs", environ: []string{"JETFIND_PROFILE=code"}, roots: []string{root}, filter: "contains", threshold: 0.9},
+		{name: "env over profile", profile: "docs", environ: []string{"JETFIND_FILTER_TYPE=fuzzy"}, roots: []string{root}, filter: "fuzzy", threshold: 0.9},
+		// The project file defines code again, replacing the user one.
+		{name: "replaced", profile: "code", filter: "fuzzy", threshold: 0.9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := LoadLayers([]FileLayer{user, project}, tc.environ, tc.profile, func(string, []byte, []string) bool { return true })
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.ScanRoots(), tc.roots) {
+				t.Errorf("Expected roots %v, obtained %v", tc.roots, cfg.Roots)
+			}
+			if cfg.Filter.Type != tc.filter {
+				t.Errorf("Expected filter type %s, obtained %s", tc.filter, cfg.Filter.Type)
+			}
+			if cfg.Filter.Threshold != tc.threshold {
+				t.Errorf("Expected threshold %v, obtained %v", tc.threshold, cfg.Filter.Threshold)
+			}
+			if cfg.Filter.Algo != "ngram" {
+				t.Errorf("Expected the algorithm of the user file, obtained %s", cfg.Filter.Algo)
+			}
+		})
+	}
+
+	cfg, err := LoadLayers([]FileLayer{user, project}, nil, "code", func(string, []byte, []string) bool { return true })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Preview.Command != "cat {}" || cfg.Tui.HighlightedFile.Foreground != NewColor("#FFFFFF") {
+		t.Errorf("Expected the settings of the code profile, obtained %+v", cfg.Preview)
+	}
+	expected := Origin{Layer: LayerProfile, Source: project.Path, Line: 4, Column: 16}
+	if origin := cfg.Origin("preview.command"); origin != expected {
+		t.Errorf("Expected origin %v, obtained %v", expected, origin)
+	}
+}
+
+func TestLoadProfileErrors(t *testing.T) {
+	user := writeLayer(t, LayerUser, "profiles:\n  docs:\n    history:\n      size: 10\n    filter:\n      threashold: 0.5\n      type: many\n    roots: [/does/not/exist]\n  a.b:\n    roots: []\n  bad: 3\n")
+
+	_, err := LoadLayers([]FileLayer{user}, nil, "docs", nil)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected configuration errors, obtained %v", err)
+	}
+	expected := []string{
+		user.Path + ":3:5: profiles.docs.history: unknown key",
+		user.Path + ":9:3: profiles.a.b: invalid profile name",
+		user.Path + ":11:8: profiles.bad: a profile must be a mapping",
+		"filter.type: invalid filter type: many. Must be one of: [fuzzy contains]",
+		"roots: invalid root: /does/not/exist. Must be an existing directory",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, obtained %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		// The validation errors are located in the profile.
+		if !strings.HasSuffix(e.Error(), expected[i]) {
+			t.Errorf("Expected %s, obtained %v", expected[i], e)
+		}
+	}
+
+	valid := writeLayer(t, LayerUser, "profiles:\n  docs: {}\n  code: {}\n")
+	_, err = LoadLayers([]FileLayer{valid}, []string{"JETFIND_PROFILE=home"}, "", nil)
+	if err == nil || err.Error() != "JETFIND_PROFILE: profiles: no profile named home. Must be one of: [code docs]" {
+		t.Errorf("Expected an unknown profile error, obtained %v", err)
+	}
+}
+
+func TestProfileCommandsNeedApproval(t *testing.T) {
+	project := writeLayer(t, LayerProject, "profiles:\n  code:\n    preview:\n      command: cat {}\n    filter:\n      type: contains\n")
+
+	var asked []string
+	cfg, err := LoadLayers([]FileLayer{project}, nil, "code", func(path string, data []byte, settings []string) bool {
+		asked = settings
+		return false
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(asked, []string{"profiles.code.preview.command"}) {
+		t.Errorf("Expected approval for the profile command, obtained %v", asked)
+	}
+	if cfg.Preview.Command != "" || cfg.Filter.Type != "contains" {
+		t.Errorf("Expected only the profile command to be dropped, obtained %+v %+v", cfg.Preview, cfg.Filter)
+	}
+}
+
+func TestScanRoots(t *testing.T) {
+	t.Setenv("HOME", "/home/user")
+	cfg := &Config{Roots: []string{"~", "~/src", "~user/src", "notes"}}
+	expected := []string{"/home/user", filepath.Join("/home/user", "src"), "~user/src", "notes"}
+	if roots := cfg.ScanRoots(); !reflect.DeepEqual(roots, expected) {
+		t.Errorf("Expected %v, obtained %v", expected, roots)
+	}
+}
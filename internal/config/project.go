@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"jetfind/internal/keymap"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FindProjectConfig looks for ProjectConfigFile in start and its parents,
+// up to the root of the git repository holding start. Outside a repository
+// only start is searched, so that a file planted in a shared directory such
+// as /tmp does not apply below it. The search stops below $HOME, whose file
+// would not belong to a project. It returns "" when there is none.
+func FindProjectConfig(start string) string {
+	home, _ := os.UserHomeDir()
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return ""
+	}
+	dirs := []string{dir}
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d || parent == home {
+			dirs = dirs[:1]
+			break
+		}
+		d = parent
+		dirs = append(dirs, d)
+	}
+
+	for _, dir := range dirs {
+		if dir == home {
+			break
+		}
+		path := filepath.Join(dir, ProjectConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// Approver decides whether the project file at path, holding data, may set
+// the settings that run commands.
+type Approver func(path string, data []byte, settings []string) bool
+
+// sensitiveSettings returns the settings of a project file that run
+// commands or name files jetfind writes: the post and preview commands,
+// those of the profiles, the history and frecency files, and the key
+// bindings that execute a command.
+func sensitiveSettings(root *yaml.Node) []string {
+	paths := []string{"post_cmd", "preview.command", "history.file", "frecency.file"}
+	if profiles := lookup(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i < len(profiles.Content); i += 2 {
+			paths = append(paths, "profiles."+profiles.Content[i].Value+".preview.command")
+		}
+	}
+	var settings []string
+	for _, path := range paths {
+		if lookup(root, path) != nil {
+			settings = append(settings, path)
+		}
+	}
+
+	keys := lookup(root, "tui.keys")
+	if keys == nil {
+		return settings
+	}
+	var bindings map[string]string
+	if err := keys.Decode(&bindings); err != nil {
+		// The file is rejected when decoded anyway.
+		return settings
+	}
+	for _, expr := range bindings {
+		actions, _ := keymap.ParseActions(expr)
+		for _, a := range actions {
+			if a.Name == keymap.Execute {
+				return append(settings, "tui.keys")
+			}
+		}
+	}
+	return settings
+}
+
+// remove deletes the value at path from a YAML mapping.
+func remove(node *yaml.Node, path string) {
+	parent, key := node, path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		parent, key = lookup(node, path[:i]), path[i+1:]
+	}
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func GetTrustFilePath() string {
+	return filepath.Join(GetStateDir(), "trusted")
+}
+
+// TrustStore records the project files allowed to run commands. A file is
+// trusted with its content, so any change needs a new approval.
+type TrustStore struct {
+	file string
+	sums map[string]string
+}
+
+// LoadTrustStore reads the approvals recorded in file, one "sha256 path"
+// line each. A missing file holds no approvals.
+func LoadTrustStore(file string) (*TrustStore, error) {
+	s := &TrustStore{file: file, sums: make(map[string]string)}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if sum, path, ok := strings.Cut(scanner.Text(), " "); ok {
+			s.sums[path] = sum
+		}
+	}
+	return s, scanner.Err()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Trusted reports whether the project file at path was approved with data.
+func (s *TrustStore) Trusted(path string, data []byte) bool {
+	return s.sums[path] == checksum(data)
+}
+
+// Trust approves the project file at path with data and saves the store.
+func (s *TrustStore) Trust(path string, data []byte) error {
+	s.sums[path] = checksum(data)
+
+	if err := os.MkdirAll(filepath.Dir(s.file), 0755); err != nil {
+		return fmt.Errorf("failed to create trust directory: %w", err)
+	}
+	var b strings.Builder
+	for _, p := range slices.Sorted(maps.Keys(s.sums)) {
+		fmt.Fprintf(&b, "%s %s\n", s.sums[p], p)
+	}
+
+	tmp := s.file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write trust file: %w", err)
+	}
+	return os.Rename(tmp, s.file)
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := filepath.Join(home, "src", "repo")
+	nested := filepath.Join(repo, "cmd", "tool")
+	for _, dir := range []string{filepath.Join(repo, ".git"), nested} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(dir string) string {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if err := os.WriteFile(path, []byte("filter:\n  type: contains\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// Files above the git root and in $HOME do not belong to the project.
+	write(home)
+	write(filepath.Join(home, "src"))
+	if path := FindProjectConfig(nested); path != "" {
+		t.Errorf("Expected no project file, obtained %s", path)
+	}
+
+	expected := write(repo)
+	if path := FindProjectConfig(nested); path != expected {
+		t.Errorf("Expected %s, obtained %s", expected, path)
+	}
+	expected = write(filepath.Join(repo, "cmd"))
+	if path := FindProjectConfig(nested); path != expected {
+		t.Errorf("Expected %s, obtained %s", expected, path)
+	}
+
+	// Outside a repository only the starting directory is searched.
+	shared := t.TempDir()
+	outside := filepath.Join(shared, "user", "dir")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write(shared)
+	if path := FindProjectConfig(outside); path != "" {
+		t.Errorf("Expected no project file, obtained %s", path)
+	}
+	expected = write(outside)
+	if path := FindProjectConfig(outside); path != expected {
+		t.Errorf("Expected %s, obtained %s", expected, path)
+	}
+}
+
+func TestProjectCommandsNeedApproval(t *testing.T) {
+	content := "post_cmd: vim\npreview:\n  command: cat {}\nhistory:\n  file: ~/.bashrc\n  size: 1\nfrecency:\n  file: ~/.profile\nfilter:\n  algorithm: ngram\ntui:\n  keys:\n    ctrl-y: copy-path\n    ctrl-o: execute(rm {})\n"
+	project := writeLayer(t, LayerProject, content)
+	user := writeLayer(t, LayerUser, "post_cmd: less\n")
+
+	var asked []string
+	cfg, err := LoadLayers([]FileLayer{user, project}, nil, "", func(path string, data []byte, settings []string) bool {
+		asked = settings
+		return false
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(asked, []string{"post_cmd", "preview.command", "history.file", "frecency.file", "tui.keys"}) {
+		t.Errorf("Expected approval for the commands and files, obtained %v", asked)
+	}
+	if cfg.PostCmd != "less" || cfg.Preview.Command != "" || cfg.Tui.Keys != nil {
+		t.Errorf("Expected the commands to be ignored, obtained %q, %q and %v", cfg.PostCmd, cfg.Preview.Command, cfg.Tui.Keys)
+	}
+	if cfg.History.File != "" || cfg.Frecency.File != "" {
+		t.Errorf("Expected the files to be ignored, obtained %q and %q", cfg.History.File, cfg.Frecency.File)
+	}
+	if cfg.Filter.Algo != "ngram" {
+		t.Errorf("Expected the other settings to be applied, obtained algorithm %s", cfg.Filter.Algo)
+	}
+
+	cfg, err = LoadLayers([]FileLayer{user, project}, nil, "", func(string, []byte, []string) bool { return true })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.PostCmd != "vim" || cfg.Preview.Command != "cat {}" || cfg.Tui.Keys["ctrl-o"] != "execute(rm {})" {
+		t.Errorf("Expected the approved commands, obtained %q, %q and %v", cfg.PostCmd, cfg.Preview.Command, cfg.Tui.Keys)
+	}
+
+	// Files of other layers and project files without commands are not
+	// subject to approval.
+	safe := writeLayer(t, LayerProject, "tui:\n  keys:\n    ctrl-y: copy-path\n")
+	if _, err := LoadLayers([]FileLayer{user, safe}, nil, "", func(string, []byte, []string) bool {
+		t.Error("Unexpected approval request")
+		return false
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestTrustStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "state", "trusted")
+	store, err := LoadTrustStore(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.Trusted("/repo/.jetfind.yml", []byte("post_cmd: vim")) {
+		t.Error("Expected an empty store")
+	}
+	if err := store.Trust("/repo/.jetfind.yml", []byte("post_cmd: vim")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store, err = LoadTrustStore(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !store.Trusted("/repo/.jetfind.yml", []byte("post_cmd: vim")) {
+		t.Error("Expected the approval to be saved")
+	}
+	if store.Trusted("/repo/.jetfind.yml", []byte("post_cmd: rm")) {
+		t.Error("Expected a changed file to need a new approval")
+	}
+}
+
+func TestFindIgnorePatterns(t *testing.T) {
+	fi, err := FindIgnoreConfig{}.FindIgnore()
+	if err != nil || fi != nil {
+		t.Fatalf("Expected no rules, obtained %v (%v)", fi, err)
+	}
+
+	fi, err = FindIgnoreConfig{Patterns: []string{"*.log", "vendor/"}}.FindIgnore()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for path, expected := range map[string]bool{"app.log": true, "vendor/lib.go": true, "main.go": false, ".env": false} {
+		if fi.ShouldIgnore(path) != expected {
+			t.Errorf("Expected %s to be ignored: %v", path, expected)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Theme presets, set with tui.theme. ThemeAuto picks the colors of the dark
+// or the light preset following the background of the terminal.
+const (
+	ThemeAuto         = ""
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeNoColor      = "no-color"
+)
+
+// Color is a hex color such as "#7DD3FC", an ANSI-256 index such as "212"
+// or a named ANSI color such as "red". It is written as a single value, or
+// as {light: ..., dark: ...} to depend on the background of the terminal.
+// Empty means the default color of the terminal.
+type Color struct {
+	Light string `yaml:"light"`
+	Dark  string `yaml:"dark"`
+}
+
+// NewColor returns a color used on any background.
+func NewColor(value string) Color {
+	return Color{Light: value, Dark: value}
+}
+
+func (c *Color) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var value string
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		*c = NewColor(value)
+		return nil
+	}
+	type plain Color
+	return node.Decode((*plain)(c))
+}
+
+func (c Color) MarshalYAML() (any, error) {
+	if c.Light == c.Dark {
+		return c.Light, nil
+	}
+	type plain Color
+	return plain(c), nil
+}
+
+func (c Color) IsZero() bool {
+	return c.Light == "" && c.Dark == ""
+}
+
+// namedColors are the ANSI colors accepted by name.
+var namedColors = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"bright-black": 8, "bright-red": 9, "bright-green": 10, "bright-yellow": 11,
+	"bright-blue": 12, "bright-magenta": 13, "bright-cyan": 14, "bright-white": 15,
+}
+
+// ANSI returns the value of a color understood by the terminal: the hex
+// color or the ANSI index, with names turned into their index.
+func ANSI(value string) string {
+	if index, ok := namedColors[value]; ok {
+		return strconv.Itoa(index)
+	}
+	return value
+}
+
+// ColorsConfig sets the colors of the elements that highlighted_file and
+// query_box do not cover.
+type ColorsConfig struct {
+	Prompt        Color `yaml:"prompt"`
+	Marked        Color `yaml:"marked"`
+	Match         Color `yaml:"match"`
+	Score         Color `yaml:"score"`
+	Status        Color `yaml:"status"`
+	Separator     Color `yaml:"separator"`
+	PreviewBorder Color `yaml:"preview_border"`
+	Error         Color `yaml:"error"`
+}
+
+// ThemeColors are the colors of every element of the TUI.
+type ThemeColors struct {
+	Prompt              Color
+	QueryText           Color
+	QueryBackground     Color
+	QueryBorder         Color
+	CursorRow           Color
+	CursorRowBackground Color
+	Marked              Color
+	Match               Color
+	Score               Color
+	Status              Color
+	Separator           Color
+	PreviewBorder       Color
+	Error               Color
+}
+
+// Themes are the presets. The no-color one relies on bold, reverse and
+// underlined text only.
+var Themes = map[string]ThemeColors{
+	ThemeDark: {
+		Prompt:          NewColor("#A5B4FC"),
+		QueryText:       NewColor("#D1D5DB"),
+		QueryBackground: NewColor("#1A1B23"),
+		QueryBorder:     NewColor("#6366F1"),
+		CursorRow:       NewColor("#7DD3FC"),
+		Marked:          NewColor("#FBBF24"),
+		Match:           NewColor("#F472B6"),
+		Score:           NewColor("#6B7280"),
+		Status:          NewColor("#9CA3AF"),
+		Separator:       NewColor("#4B5563"),
+		PreviewBorder:   NewColor("#4B5563"),
+		Error:           NewColor("#F87171"),
+	},
+	ThemeLight: {
+		Prompt:          NewColor("#4338CA"),
+		QueryText:       NewColor("#1F2937"),
+		QueryBackground: NewColor("#F3F4F6"),
+		QueryBorder:     NewColor("#6366F1"),
+		CursorRow:       NewColor("#0369A1"),
+		Marked:          NewColor("#B45309"),
+		Match:           NewColor("#BE185D"),
+		Score:           NewColor("#9CA3AF"),
+		Status:          NewColor("#6B7280"),
+		Separator:       NewColor("#D1D5DB"),
+		PreviewBorder:   NewColor("#D1D5DB"),
+		Error:           NewColor("#DC2626"),
+	},
+	ThemeHighContrast: {
+		Prompt:              NewColor("bright-white"),
+		QueryText:           NewColor("bright-white"),
+		QueryBackground:     NewColor("black"),
+		QueryBorder:         NewColor("bright-white"),
+		CursorRow:           NewColor("black"),
+		CursorRowBackground: NewColor("bright-yellow"),
+		Marked:              NewColor("bright-green"),
+		Match:               NewColor("bright-cyan"),
+		Score:               NewColor("bright-white"),
+		Status:              NewColor("bright-white"),
+		Separator:           NewColor("bright-white"),
+		PreviewBorder:       NewColor("bright-white"),
+		Error:               NewColor("bright-red"),
+	},
+	ThemeNoColor: {},
+}
+
+// ThemeNames returns the names of the presets, sorted.
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(Themes))
+}
+
+// ThemeColors returns the colors of the configured preset, overridden by
+// the colors set in t. The automatic preset uses the light and dark presets
+// for each background, unless NO_COLOR is set, which makes it no-color.
+func (t TuiConfig) ThemeColors() ThemeColors {
+	colors, ok := Themes[t.Theme]
+	if t.Theme == ThemeAuto {
+		colors = autoTheme()
+		if os.Getenv("NO_COLOR") != "" {
+			colors = Themes[ThemeNoColor]
+		}
+	} else if !ok {
+		colors = Themes[ThemeDark]
+	}
+
+	set := func(color *Color, override Color) {
+		if !override.IsZero() {
+			*color = override
+		}
+	}
+	set(&colors.Prompt, t.Colors.Prompt)
+	set(&colors.QueryText, t.QueryBox.TextForeground)
+	set(&colors.QueryBackground, t.QueryBox.TextBackground)
+	set(&colors.QueryBorder, t.QueryBox.BorderForeground)
+	set(&colors.CursorRow, t.HighlightedFile.Foreground)
+	set(&colors.CursorRowBackground, t.HighlightedFile.Background)
+	set(&colors.Marked, t.Colors.Marked)
+	set(&colors.Match, t.Colors.Match)
+	set(&colors.Score, t.Colors.Score)
+	set(&colors.Status, t.Colors.Status)
+	set(&colors.Separator, t.Colors.Separator)
+	set(&colors.PreviewBorder, t.Colors.PreviewBorder)
+	set(&colors.Error, t.Colors.Error)
+	return colors
+}
+
+// autoTheme combines the light and the dark presets.
+func autoTheme() ThemeColors {
+	var colors ThemeColors
+	c := reflect.ValueOf(&colors).Elem()
+	light, dark := reflect.ValueOf(Themes[ThemeLight]), reflect.ValueOf(Themes[ThemeDark])
+	for i := range c.NumField() {
+		c.Field(i).Set(reflect.ValueOf(Color{
+			Light: light.Field(i).Interface().(Color).Light,
+			Dark:  dark.Field(i).Interface().(Color).Dark,
+		}))
+	}
+	return colors
+}
+
+// isValidColor reports whether color is empty, a hex color, an ANSI-256
+// index or a named ANSI color.
+func isValidColor(color string) bool {
+	if _, ok := namedColors[color]; ok {
+		return true
+	}
+	if index, err := strconv.Atoi(color); err == nil {
+		return index >= 0 && index <= 255 && color == strconv.Itoa(index)
+	}
+	return isValidHexColor(color)
+}
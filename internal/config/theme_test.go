@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestThemeColors(t *testing.T) {
+	dark, light := Themes[ThemeDark], Themes[ThemeLight]
+
+	testCases := []struct {
+		name      string
+		tui       TuiConfig
+		noColor   string
+		cursorRow Color
+		status    Color
+	}{
+		{name: "auto", cursorRow: Color{Light: light.CursorRow.Light, Dark: dark.CursorRow.Dark}, status: Color{Light: light.Status.Light, Dark: dark.Status.Dark}},
+		{name: "preset", tui: TuiConfig{Theme: ThemeLight}, cursorRow: light.CursorRow, status: light.Status},
+		{name: "no color", noColor: "1"},
+		{name: "no color with a preset", tui: TuiConfig{Theme: ThemeDark}, noColor: "1", cursorRow: dark.CursorRow, status: dark.Status},
+		{
+			name: "overrides",
+			tui: TuiConfig{
+				Theme:           ThemeHighContrast,
+				HighlightedFile: HighlightedFileConfig{Foreground: NewColor("212")},
+				Colors:          ColorsConfig{Status: Color{Light: "black", Dark: "white"}},
+			},
+			cursorRow: NewColor("212"),
+			status:    Color{Light: "black", Dark: "white"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			colors := tc.tui.ThemeColors()
+			if colors.CursorRow != tc.cursorRow {
+				t.Errorf("Expected cursor row %v, obtained %v", tc.cursorRow, colors.CursorRow)
+			}
+			if colors.Status != tc.status {
+				t.Errorf("Expected status %v, obtained %v", tc.status, colors.Status)
+			}
+		})
+	}
+}
+
+func TestColorYAML(t *testing.T) {
+	testCases := []struct {
+		yaml     string
+		expected Color
+	}{
+		{yaml: `"#7DD3FC"`, expected: NewColor("#7DD3FC")},
+		{yaml: `212`, expected: NewColor("212")},
+		{yaml: `{light: "#0369A1", dark: bright-cyan}`, expected: Color{Light: "#0369A1", Dark: "bright-cyan"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.yaml, func(t *testing.T) {
+			var c Color
+			if err := yaml.Unmarshal([]byte(tc.yaml), &c); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if c != tc.expected {
+				t.Errorf("Expected %v, obtained %v", tc.expected, c)
+			}
+
+			data, err := yaml.Marshal(c)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var again Color
+			if err := yaml.Unmarshal(data, &again); err != nil || again != c {
+				t.Errorf("Expected %v after a round trip, obtained %v (%v)", c, again, err)
+			}
+		})
+	}
+}
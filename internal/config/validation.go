@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"jetfind/internal/keymap"
+	"jetfind/internal/scanengine"
+	"math"
 	"os"
-	"path/filepath"
+	"reflect"
+	"slices"
 )
 
 func contains(slice []string, item string) bool {
@@ -39,44 +43,102 @@ func isValidHexColor(color string) bool {
 	return true
 }
 
+// Validate checks every setting and reports all the invalid ones as
+// Errors, located from the origins of the settings.
 func (c *Config) Validate() error {
+	errs := c.validate()
+	errs.locate(c)
+	return errs.err()
+}
+
+func (c *Config) validate() Errors {
+	var errs Errors
+	add := func(path string, err error) {
+		errs = append(errs, &FieldError{Path: path, Message: err.Error()})
+	}
 
-	validFilterTypes := []string{"fuzzy", "contains"}
-	if !contains(validFilterTypes, c.Filter.Type) {
-		return fmt.Errorf("invalid filter type: %s. Must be one of: %v", c.Filter.Type, validFilterTypes)
+	if !contains(scanengine.FilterTypes, c.Filter.Type) {
+		add("filter.type", fmt.Errorf("invalid filter type: %s. Must be one of: %v", c.Filter.Type, scanengine.FilterTypes))
 	}
 
-	if c.Filter.Type == "fuzzy" {
-		validAlgos := []string{"jarowinkler", "ngram", "levenshtein"}
-		if !contains(validAlgos, c.Filter.Algo) {
-			return fmt.Errorf("invalid filter algorithm: %s. Must be one of: %v", c.Filter.Algo, validAlgos)
+	if c.Filter.Type == scanengine.TypeFuzzy {
+		if !contains(scanengine.Algos, c.Filter.Algo) {
+			add("filter.algorithm", fmt.Errorf("invalid filter algorithm: %s. Must be one of: %v", c.Filter.Algo, scanengine.Algos))
 		}
 
-		if c.Filter.Threashold < 0 || c.Filter.Threashold > 1 {
-			return fmt.Errorf("invalid filter threashold: %.2f. Must be in the [0, 1] interval", c.Filter.Threashold)
+		if math.IsNaN(c.Filter.Threshold) || c.Filter.Threshold < 0 || c.Filter.Threshold > 1 {
+			add("filter.threshold", fmt.Errorf("invalid filter threshold: %.2f. Must be in the [0, 1] interval", c.Filter.Threshold))
+		}
+	}
+
+	if c.Filter.Case != "" && !contains(scanengine.CaseModes, c.Filter.Case) {
+		add("filter.case", fmt.Errorf("invalid filter case: %s. Must be one of: %v", c.Filter.Case, scanengine.CaseModes))
+	}
+
+	if c.Filter.Scope != "" && !contains(scanengine.Scopes, c.Filter.Scope) {
+		add("filter.scope", fmt.Errorf("invalid filter scope: %s. Must be one of: %v", c.Filter.Scope, scanengine.Scopes))
+	}
+
+	validNormalizations := []string{"", "none", "nfc", "nfd"}
+	if !contains(validNormalizations, c.Filter.Normalization) {
+		add("filter.normalization", fmt.Errorf("invalid filter normalization: %s. Must be one of: %v", c.Filter.Normalization, validNormalizations[1:]))
+	}
+
+	for i, key := range c.Filter.Tiebreak {
+		if !contains(scanengine.ValidTiebreaks, key) {
+			add("filter.tiebreak", fmt.Errorf("invalid filter tiebreak: %s. Must be one of: %v", key, scanengine.ValidTiebreaks))
 		}
+		if contains(c.Filter.Tiebreak[:i], key) {
+			add("filter.tiebreak", fmt.Errorf("duplicated filter tiebreak: %s", key))
+		}
+	}
+
+	if c.Frecency.Weight < 0 || c.Frecency.Weight > 1 {
+		add("frecency.weight", fmt.Errorf("invalid frecency weight: %.2f. Must be in the [0, 1] interval", c.Frecency.Weight))
 	}
 
 	if c.Findignore.Enable {
-		findignorePath := filepath.Join(GetConfigDir(), ".findignore")
+		findignorePath := GetFindIgnorePath()
 		if _, err := os.Stat(findignorePath); os.IsNotExist(err) {
-			return fmt.Errorf("config file does not exist: %s", findignorePath)
+			add("findignore.enable", fmt.Errorf("config file does not exist: %s", findignorePath))
+		}
+	}
+
+	for _, root := range c.ScanRoots() {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			add("roots", fmt.Errorf("invalid root: %s. Must be an existing directory", root))
 		}
 	}
 
-	colorFields := []string{
-		c.Tui.HighlightedFile.Foreground,
-		c.Tui.HighlightedFile.Background,
-		c.Tui.QueryBox.TextForeground,
-		c.Tui.QueryBox.TextBackground,
-		c.Tui.QueryBox.BorderForeground,
+	if _, ok := Themes[c.Tui.Theme]; !ok && c.Tui.Theme != ThemeAuto {
+		add("tui.theme", fmt.Errorf("invalid theme: %s. Must be one of: %v", c.Tui.Theme, ThemeNames()))
 	}
 
-	for _, color := range colorFields {
-		if color != "" && !isValidHexColor(color) {
-			return fmt.Errorf("invalid hex color format: %s", color)
+	eachSetting(reflect.ValueOf(c).Elem(), "", func(path string, v reflect.Value) error {
+		if color, ok := v.Interface().(Color); ok {
+			for _, value := range slices.Compact([]string{color.Light, color.Dark}) {
+				if !isValidColor(value) {
+					add(path, fmt.Errorf("invalid color: %s. Must be a hex color, an ANSI-256 index or an ANSI color name", value))
+				}
+			}
 		}
+		return nil
+	})
+
+	if c.Tui.Height != "" {
+		if _, _, err := ParseHeight(c.Tui.Height); err != nil {
+			add("tui.height", err)
+		}
+	}
+
+	validLayouts := []string{"", LayoutDefault, LayoutReverse}
+	if !contains(validLayouts, c.Tui.Layout) {
+		add("tui.layout", fmt.Errorf("invalid layout: %s. Must be one of: %v", c.Tui.Layout, validLayouts[1:]))
+	}
+
+	if _, err := keymap.ParseBindings(c.Tui.Keys); err != nil {
+		add("tui.keys", fmt.Errorf("invalid tui keys: %w", err))
 	}
 
-	return nil
+	return errs
 }
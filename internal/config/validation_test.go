@@ -1,6 +1,8 @@
 package config
 
 import (
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -32,29 +34,180 @@ func TestValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "threshold not a number",
+			config: Config{
+				Filter: FilterConfig{
+					Type:      "fuzzy",
+					Algo:      "jarowinkler",
+					Threshold: math.NaN(),
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "threshold out of range",
 			config: Config{
 				Filter: FilterConfig{
-					Type:       "fuzzy",
-					Algo:       "jarowinkler",
-					Threashold: 1.5,
+					Type:      "fuzzy",
+					Algo:      "jarowinkler",
+					Threshold: 1.5,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid case",
+			config: Config{
+				Filter: FilterConfig{
+					Type: "contains",
+					Case: "upper",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid scope",
+			config: Config{
+				Filter: FilterConfig{
+					Type:  "contains",
+					Scope: "dirname",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid normalization",
+			config: Config{
+				Filter: FilterConfig{
+					Type:          "contains",
+					Normalization: "nfkc",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid tiebreak",
+			config: Config{
+				Filter: FilterConfig{
+					Type:     "contains",
+					Tiebreak: []string{"score", "size"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated tiebreak",
+			config: Config{
+				Filter: FilterConfig{
+					Type:     "contains",
+					Tiebreak: []string{"path", "path"},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "frecency weight out of range",
+			config: Config{
+				Filter:   Default.Filter,
+				Frecency: FrecencyConfig{Enable: true, Weight: 1.5},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid hex color",
 			config: Config{
 				Filter: Default.Filter,
 				Tui: TuiConfig{
 					HighlightedFile: HighlightedFileConfig{
-						Foreground: "not_a_hex_color",
+						Foreground: NewColor("not_a_hex_color"),
 					},
 				},
 			},
 			wantErr: true,
 		},
+		{
+			name: "theme with ANSI, named and adaptive colors",
+			config: Config{
+				Filter: Default.Filter,
+				Tui: TuiConfig{
+					Theme:           ThemeLight,
+					HighlightedFile: HighlightedFileConfig{Foreground: NewColor("212"), Background: NewColor("bright-black")},
+					Colors:          ColorsConfig{Match: Color{Light: "#BE185D", Dark: "magenta"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid adaptive color",
+			config: Config{
+				Filter: Default.Filter,
+				Tui:    TuiConfig{Colors: ColorsConfig{Status: Color{Light: "#6B7280", Dark: "256"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid theme",
+			config: Config{
+				Filter: Default.Filter,
+				Tui:    TuiConfig{Theme: "solarized"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inline height and reverse layout",
+			config: Config{
+				Filter: Default.Filter,
+				Tui:    TuiConfig{Height: "40%", Layout: "reverse"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid height",
+			config: Config{
+				Filter: Default.Filter,
+				Tui:    TuiConfig{Height: "150%"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid layout",
+			config: Config{
+				Filter: Default.Filter,
+				Tui:    TuiConfig{Layout: "sideways"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom key bindings",
+			config: Config{
+				Filter: Default.Filter,
+				Tui: TuiConfig{
+					Keys: map[string]string{"ctrl-y": "copy-path", "ctrl-o": "execute(vim {})"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown key action",
+			config: Config{
+				Filter: Default.Filter,
+				Tui: TuiConfig{
+					Keys: map[string]string{"ctrl-y": "yank"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting key chords",
+			config: Config{
+				Filter: Default.Filter,
+				Tui: TuiConfig{
+					Keys: map[string]string{"ctrl-y": "copy-path", "ctrl+y": "accept"},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,6 +244,33 @@ func TestHexColorValidation(t *testing.T) {
 	}
 }
 
+func TestColorValidation(t *testing.T) {
+	tests := []struct {
+		color string
+		valid bool
+	}{
+		{"#FFFFFF", true},
+		{"0", true},
+		{"212", true},
+		{"255", true},
+		{"256", false},
+		{"-1", false},
+		{"007", false},
+		{"red", true},
+		{"bright-cyan", true},
+		{"Red", false},
+		{"purple", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			if got := isValidColor(tt.color); got != tt.valid {
+				t.Errorf("isValidColor(%q) = %v, want %v", tt.color, got, tt.valid)
+			}
+		})
+	}
+}
+
 func TestContains(t *testing.T) {
 	slice := []string{"apple", "banana", "cherry"}
 
@@ -102,3 +282,18 @@ func TestContains(t *testing.T) {
 		t.Error("Expected contains to return false for 'grape'")
 	}
 }
+
+func TestValidationReportsAllErrors(t *testing.T) {
+	cfg := *Default
+	cfg.Filter.Type = "regex"
+	cfg.Frecency.Weight = 2
+	cfg.Tui.Layout = "sideways"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected an error, got none")
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 3 {
+		t.Errorf("Expected 3 errors, obtained %q", lines)
+	}
+}
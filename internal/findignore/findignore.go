@@ -43,9 +43,13 @@ func New(filename string, ignoreHidden bool) (*FindIgnore, error) {
 	if err != nil {
 		return nil, err
 	}
-	s := strings.Split(string(lines), "\n")
+	return Parse(strings.Split(string(lines), "\n"), ignoreHidden)
+}
+
+// Parse builds the rules from the lines of a .findignore file.
+func Parse(lines []string, ignoreHidden bool) (*FindIgnore, error) {
 	patterns := make([]IgnorePattern, 0)
-	for _, line := range s {
+	for _, line := range lines {
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
@@ -0,0 +1,140 @@
+package frecency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Time after which the weight of past selections is halved.
+const halfLife = 7 * 24 * time.Hour
+
+type Entry struct {
+	Path     string    `json:"path"`
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Store keeps how often and how recently files were selected. It is not
+// safe for concurrent writes, but Score can be called concurrently once the
+// store is loaded.
+type Store struct {
+	path    string
+	entries map[string]*Entry
+}
+
+// Load reads the store at path. A missing file is an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, entries: make(map[string]*Entry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frecency file: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse frecency file: %w", err)
+	}
+	for i := range entries {
+		s.entries[entries[i].Path] = &entries[i]
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create frecency directory: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write frecency file: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Record registers a selection of path at the given time.
+func (s *Store) Record(path string, now time.Time) {
+	e, ok := s.entries[path]
+	if !ok {
+		e = &Entry{Path: path}
+		s.entries[path] = e
+	}
+	e.Count++
+	e.LastUsed = now
+}
+
+func (s *Store) Forget(path string) bool {
+	if _, ok := s.entries[path]; !ok {
+		return false
+	}
+	delete(s.entries, path)
+	return true
+}
+
+func (s *Store) Clear() {
+	s.entries = make(map[string]*Entry)
+}
+
+// Frecency is the number of selections decayed by the time elapsed since
+// the last one.
+func (e Entry) Frecency(now time.Time) float64 {
+	age := max(0, now.Sub(e.LastUsed))
+	return float64(e.Count) * math.Exp2(-float64(age)/float64(halfLife))
+}
+
+// Score returns the frecency of path mapped to [0, 1); unknown paths score 0.
+func (s *Store) Score(path string, now time.Time) float64 {
+	e, ok := s.entries[path]
+	if !ok {
+		return 0
+	}
+	f := e.Frecency(now)
+	return f / (f + 1)
+}
+
+// Entries returns the stored entries, highest frecency first.
+func (s *Store) Entries(now time.Time) []Entry {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		fi, fj := entries[i].Frecency(now), entries[j].Frecency(now)
+		if fi != fj {
+			return fi > fj
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// Boost returns a function scoring paths relative to root, as expected by
+// scanengine.Ranking.
+func (s *Store) Boost(root string, now time.Time) func(path string) float64 {
+	return func(path string) float64 {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		return s.Score(path, now)
+	}
+}
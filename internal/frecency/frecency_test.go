@@ -0,0 +1,96 @@
+package frecency
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "frecency.json")
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error for a missing file: %v", err)
+	}
+	s.Record("/project/main.go", now.Add(-time.Hour))
+	s.Record("/project/main.go", now)
+	s.Record("/project/README.md", now)
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() returned an error: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	entries := reloaded.Entries(now)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Path != "/project/main.go" || entries[0].Count != 2 {
+		t.Errorf("Expected /project/main.go with 2 selections first, got %+v", entries[0])
+	}
+}
+
+func TestScore(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	s := &Store{entries: map[string]*Entry{
+		"/recent":   {Path: "/recent", Count: 3, LastUsed: now},
+		"/old":      {Path: "/old", Count: 3, LastUsed: now.Add(-halfLife)},
+		"/frequent": {Path: "/frequent", Count: 30, LastUsed: now.Add(-halfLife)},
+	}}
+
+	if got := s.Score("/unknown", now); got != 0 {
+		t.Errorf("Expected 0 for an unknown path, got %v", got)
+	}
+	if got := s.Entries(now)[2].Frecency(now); math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("Expected frecency halved after one half-life, got %v", got)
+	}
+	if s.Score("/recent", now) <= s.Score("/old", now) {
+		t.Error("Recent selections must score higher than old ones")
+	}
+	if s.Score("/frequent", now) <= s.Score("/recent", now) {
+		t.Error("Frequent selections must score higher than rare ones")
+	}
+	for path := range s.entries {
+		if score := s.Score(path, now); score < 0 || score >= 1 {
+			t.Errorf("Score(%s) = %v out of [0, 1)", path, score)
+		}
+	}
+}
+
+func TestBoostRelativePaths(t *testing.T) {
+	now := time.Now()
+	s := &Store{entries: map[string]*Entry{
+		"/project/main.go": {Path: "/project/main.go", Count: 1, LastUsed: now},
+	}}
+
+	boost := s.Boost("/project", now)
+	if boost("main.go") == 0 {
+		t.Error("Expected a relative path to be resolved against the root")
+	}
+	if boost("/project/main.go") != boost("main.go") {
+		t.Error("Expected absolute and relative paths to score the same")
+	}
+}
+
+func TestForgetAndClear(t *testing.T) {
+	now := time.Now()
+	s := &Store{entries: map[string]*Entry{}}
+	s.Record("/a", now)
+	s.Record("/b", now)
+
+	if !s.Forget("/a") {
+		t.Error("Forget() of a tracked path returned false")
+	}
+	if s.Forget("/a") {
+		t.Error("Forget() of an untracked path returned true")
+	}
+	s.Clear()
+	if len(s.Entries(now)) != 0 {
+		t.Error("Clear() left entries behind")
+	}
+}
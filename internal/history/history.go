@@ -0,0 +1,159 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Entry struct {
+	Query string    `json:"query"`
+	Root  string    `json:"root"`
+	Time  time.Time `json:"time"`
+}
+
+// History is the list of submitted queries, oldest first, persisted as one
+// JSON object per line.
+type History struct {
+	path    string
+	limit   int
+	entries []Entry
+}
+
+// Load reads the history file at path. A missing file is an empty history.
+// Only the most recent limit entries are kept.
+func Load(path string, limit int) (*History, error) {
+	h := &History{path: path, limit: limit}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Query == "" {
+			continue
+		}
+		h.entries = append(h.entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	h.trim()
+	return h, nil
+}
+
+func (h *History) trim() bool {
+	if h.limit <= 0 || len(h.entries) <= h.limit {
+		return false
+	}
+	h.entries = h.entries[len(h.entries)-h.limit:]
+	return true
+}
+
+func (h *History) Entries() []Entry {
+	return h.entries
+}
+
+// Add records a submitted query and saves the history. Submitting the same
+// query twice in a row in the same root stores it once.
+func (h *History) Add(query, root string) error {
+	query = strings.TrimSpace(query)
+	if query == "" || h.limit <= 0 {
+		return nil
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1].Query == query && h.entries[n-1].Root == root {
+		return nil
+	}
+
+	e := Entry{Query: query, Root: root, Time: time.Now()}
+	h.entries = append(h.entries, e)
+	if h.trim() {
+		return h.save()
+	}
+	return h.append(e)
+}
+
+func (h *History) append(e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return nil
+}
+
+func (h *History) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	var b strings.Builder
+	for _, e := range h.entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	return os.Rename(tmp, h.path)
+}
+
+// Queries returns the queries submitted in root, most recent first. An
+// empty root returns the queries of every root.
+func (h *History) Queries(root string) []string {
+	queries := make([]string, 0, len(h.entries))
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		e := h.entries[i]
+		if root != "" && e.Root != root {
+			continue
+		}
+		if len(queries) > 0 && queries[len(queries)-1] == e.Query {
+			continue
+		}
+		queries = append(queries, e.Query)
+	}
+	return queries
+}
+
+// Search returns the index in Entries of the most recent entry containing
+// term, looking only at entries older than before. It returns -1 when there
+// is no match.
+func (h *History) Search(term string, before int) int {
+	before = min(before, len(h.entries))
+	for i := before - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(h.entries[i].Query), strings.ToLower(term)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	h, err := Load(filepath.Join(t.TempDir(), "missing"), 10)
+	if err != nil {
+		t.Fatalf("Load() returned an error for a missing file: %v", err)
+	}
+	if len(h.Entries()) != 0 {
+		t.Errorf("Expected an empty history, got %d entries", len(h.Entries()))
+	}
+}
+
+func TestAddAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "history")
+
+	h, err := Load(path, 10)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	for _, q := range []string{"main", "main", "  readme ", ""} {
+		if err := h.Add(q, "/project"); err != nil {
+			t.Fatalf("Add(%q) returned an error: %v", q, err)
+		}
+	}
+	if err := h.Add("main", "/other"); err != nil {
+		t.Fatalf("Add() returned an error: %v", err)
+	}
+
+	reloaded, err := Load(path, 10)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+
+	var queries []string
+	for _, e := range reloaded.Entries() {
+		queries = append(queries, e.Root+":"+e.Query)
+	}
+	expected := []string{"/project:main", "/project:readme", "/other:main"}
+	if !reflect.DeepEqual(queries, expected) {
+		t.Errorf("Expected %v, got %v", expected, queries)
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, _ := Load(path, 3)
+	for _, q := range []string{"a", "b", "c", "d", "e"} {
+		if err := h.Add(q, "/"); err != nil {
+			t.Fatalf("Add(%q) returned an error: %v", q, err)
+		}
+	}
+
+	reloaded, err := Load(path, 100)
+	if err != nil {
+		t.Fatalf("Load() returned an error: %v", err)
+	}
+	if got := reloaded.Queries(""); !reflect.DeepEqual(got, []string{"e", "d", "c"}) {
+		t.Errorf("Expected [e d c], got %v", got)
+	}
+
+	smaller, _ := Load(path, 2)
+	if got := smaller.Queries(""); !reflect.DeepEqual(got, []string{"e", "d"}) {
+		t.Errorf("Expected [e d], got %v", got)
+	}
+}
+
+func TestDisabledHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, _ := Load(path, 0)
+	if err := h.Add("main", "/"); err != nil {
+		t.Fatalf("Add() returned an error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("A disabled history must not write the history file")
+	}
+}
+
+func TestQueriesAndSearch(t *testing.T) {
+	h := &History{limit: 10, entries: []Entry{
+		{Query: "main.go", Root: "/a"},
+		{Query: "readme", Root: "/b"},
+		{Query: "Makefile", Root: "/a"},
+		{Query: "main.go", Root: "/a"},
+	}}
+
+	if got := h.Queries("/a"); !reflect.DeepEqual(got, []string{"main.go", "Makefile", "main.go"}) {
+		t.Errorf("Queries(/a) = %v", got)
+	}
+	if got := h.Queries("/b"); !reflect.DeepEqual(got, []string{"readme"}) {
+		t.Errorf("Queries(/b) = %v", got)
+	}
+
+	testCases := []struct {
+		term     string
+		before   int
+		expected int
+	}{
+		{term: "ma", before: 4, expected: 3},
+		{term: "ma", before: 3, expected: 2},
+		{term: "ma", before: 2, expected: 0},
+		{term: "ma", before: 0, expected: -1},
+		{term: "README", before: 4, expected: 1},
+		{term: "nothing", before: 4, expected: -1},
+	}
+	for _, tc := range testCases {
+		if got := h.Search(tc.term, tc.before); got != tc.expected {
+			t.Errorf("Search(%q, %d) = %d, expected %d", tc.term, tc.before, got, tc.expected)
+		}
+	}
+}
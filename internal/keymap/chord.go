@@ -0,0 +1,122 @@
+package keymap
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Names accepted for keys that are not a single character, mapped to the
+// names bubbletea gives them.
+var keyNames = map[string]string{
+	"enter":     "enter",
+	"return":    "enter",
+	"esc":       "esc",
+	"escape":    "esc",
+	"tab":       "tab",
+	"btab":      "shift+tab",
+	"space":     " ",
+	"bspace":    "backspace",
+	"bs":        "backspace",
+	"backspace": "backspace",
+	"del":       "delete",
+	"delete":    "delete",
+	"insert":    "insert",
+	"up":        "up",
+	"down":      "down",
+	"left":      "left",
+	"right":     "right",
+	"home":      "home",
+	"end":       "end",
+	"pgup":      "pgup",
+	"page-up":   "pgup",
+	"pgdn":      "pgdown",
+	"pgdown":    "pgdown",
+	"page-down": "pgdown",
+}
+
+func init() {
+	for i := 1; i <= 20; i++ {
+		name := fmt.Sprintf("f%d", i)
+		keyNames[name] = name
+	}
+}
+
+// Keys that can be combined with ctrl or shift, besides letters for ctrl.
+var (
+	ctrlKeys  = []string{"up", "down", "left", "right", "home", "end", "pgup", "pgdown", "@", "\\", "]", "^", "_"}
+	shiftKeys = []string{"up", "down", "left", "right", "home", "end", "tab"}
+)
+
+func isOneOf(key string, keys []string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseKey converts a chord such as "ctrl-a", "alt+enter", "page-down" or
+// "?" to the name bubbletea gives to the key.
+func ParseKey(chord string) (string, error) {
+	if utf8.RuneCountInString(chord) == 1 {
+		return chord, nil
+	}
+
+	var ctrl, alt, shift bool
+	base := chord
+	for {
+		lower := strings.ToLower(base)
+		switch {
+		case hasModifier(lower, "ctrl"):
+			ctrl, base = true, base[5:]
+		case hasModifier(lower, "alt"):
+			alt, base = true, base[4:]
+		case hasModifier(lower, "shift"):
+			shift, base = true, base[6:]
+		default:
+			return composeKey(chord, base, ctrl, alt, shift)
+		}
+	}
+}
+
+func hasModifier(chord, modifier string) bool {
+	return len(chord) > len(modifier)+1 &&
+		strings.HasPrefix(chord, modifier) &&
+		(chord[len(modifier)] == '-' || chord[len(modifier)] == '+')
+}
+
+func composeKey(chord, base string, ctrl, alt, shift bool) (string, error) {
+	if utf8.RuneCountInString(base) != 1 {
+		name, ok := keyNames[strings.ToLower(base)]
+		if !ok {
+			return "", fmt.Errorf("unknown key: %q", chord)
+		}
+		base = name
+	}
+
+	if shift {
+		if !isOneOf(base, shiftKeys) {
+			return "", fmt.Errorf("unsupported key: %q", chord)
+		}
+		base = "shift+" + base
+	}
+	if ctrl {
+		switch {
+		case base == " ":
+			base = "@"
+		case len(base) == 1 && base[0] >= 'A' && base[0] <= 'Z':
+			base = strings.ToLower(base)
+		}
+		letter := len(base) == 1 && base[0] >= 'a' && base[0] <= 'z'
+		if !letter && !isOneOf(strings.TrimPrefix(base, "shift+"), ctrlKeys) {
+			return "", fmt.Errorf("unsupported key: %q", chord)
+		}
+		base = "ctrl+" + base
+	}
+	if alt {
+		base = "alt+" + base
+	}
+	return base, nil
+}
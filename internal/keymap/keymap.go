@@ -0,0 +1,297 @@
+// Package keymap maps key chords to the actions of the TUI. Chords and
+// actions use the fzf syntax, e.g. "ctrl-y:copy-path" or
+// "ctrl-o:execute(vim {})".
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	Accept        = "accept"
+	Abort         = "abort"
+	Up            = "up"
+	Down          = "down"
+	PageUp        = "page-up"
+	PageDown      = "page-down"
+	HalfPageUp    = "half-page-up"
+	HalfPageDown  = "half-page-down"
+	First         = "first"
+	Last          = "last"
+	ToggleMark    = "toggle-mark"
+	PreviewToggle = "preview-toggle"
+	CopyPath      = "copy-path"
+	CycleFilter   = "cycle-filter"
+	CycleCase     = "cycle-case"
+	CycleScope    = "cycle-scope"
+	ThresholdUp   = "threshold-up"
+	ThresholdDown = "threshold-down"
+	PrevHistory   = "prev-history"
+	NextHistory   = "next-history"
+	HistorySearch = "history-search"
+	ClearQuery    = "clear-query"
+	Help          = "help"
+	Ignore        = "ignore"
+	Execute       = "execute"
+)
+
+// Actions describes every bindable action.
+var Actions = map[string]string{
+	Accept:        "Select the highlighted or marked results",
+	Abort:         "Quit without selecting",
+	Up:            "Move the highlight up",
+	Down:          "Move the highlight down",
+	PageUp:        "Move up by a page",
+	PageDown:      "Move down by a page",
+	HalfPageUp:    "Move up by half a page",
+	HalfPageDown:  "Move down by half a page",
+	First:         "Jump to the first result",
+	Last:          "Jump to the last result",
+	ToggleMark:    "Mark or unmark the highlighted result",
+	PreviewToggle: "Show or hide the preview",
+	CopyPath:      "Copy the highlighted path to the clipboard",
+	CycleFilter:   "Switch to the next filter type and algorithm",
+	CycleCase:     "Switch to the next case mode",
+	CycleScope:    "Switch to the next part of the paths matched",
+	ThresholdUp:   "Raise the fuzzy threshold, for fewer results",
+	ThresholdDown: "Lower the fuzzy threshold, for more results",
+	PrevHistory:   "Recall the previous query",
+	NextHistory:   "Recall the next query",
+	HistorySearch: "Search the query history",
+	ClearQuery:    "Clear the query",
+	Help:          "Show or hide this help",
+	Ignore:        "Do nothing",
+	Execute:       "Run a shell command, {} is the highlighted path and {+} the marked ones",
+}
+
+// Action is a named action. Only execute takes an argument, the command.
+type Action struct {
+	Name string
+	Arg  string
+}
+
+func (a Action) String() string {
+	if a.Name == Execute {
+		return Execute + "(" + a.Arg + ")"
+	}
+	return a.Name
+}
+
+// Binding attaches a chain of actions to a key. Key uses the names of
+// bubbletea key messages, Expr is the action chain as written by the user.
+type Binding struct {
+	Key     string
+	Expr    string
+	Actions []Action
+}
+
+// Keymap maps bubbletea key names to action chains.
+type Keymap map[string][]Action
+
+func bind(actions ...string) []Action {
+	chain := make([]Action, len(actions))
+	for i, name := range actions {
+		chain[i] = Action{Name: name}
+	}
+	return chain
+}
+
+func Default() Keymap {
+	return Keymap{
+		"enter":       bind(Accept),
+		"ctrl+c":      bind(Abort),
+		"esc":         bind(Abort),
+		"up":          bind(Up),
+		"down":        bind(Down),
+		"pgup":        bind(PageUp),
+		"pgdown":      bind(PageDown),
+		"ctrl+pgup":   bind(HalfPageUp),
+		"ctrl+pgdown": bind(HalfPageDown),
+		"ctrl+d":      bind(HalfPageDown),
+		"ctrl+home":   bind(First),
+		"ctrl+end":    bind(Last),
+		"tab":         bind(ToggleMark, Down),
+		"shift+tab":   bind(ToggleMark, Up),
+		"alt+p":       bind(PreviewToggle),
+		"ctrl+y":      bind(CopyPath),
+		"ctrl+t":      bind(CycleFilter),
+		"alt+c":       bind(CycleCase),
+		"alt+s":       bind(CycleScope),
+		"alt+up":      bind(ThresholdUp),
+		"alt+down":    bind(ThresholdDown),
+		"ctrl+p":      bind(PrevHistory),
+		"ctrl+n":      bind(NextHistory),
+		"ctrl+r":      bind(HistorySearch),
+		"f1":          bind(Help),
+	}
+}
+
+// Bind applies bindings over k, replacing the previous action chains of
+// their keys.
+func (k Keymap) Bind(bindings []Binding) {
+	for _, b := range bindings {
+		k[b.Key] = b.Actions
+	}
+}
+
+// Bindings returns the bindings of k sorted by key.
+func (k Keymap) Bindings() []Binding {
+	bindings := make([]Binding, 0, len(k))
+	for key, actions := range k {
+		names := make([]string, len(actions))
+		for i, a := range actions {
+			names[i] = a.String()
+		}
+		bindings = append(bindings, Binding{Key: key, Expr: strings.Join(names, "+"), Actions: actions})
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Key < bindings[j].Key
+	})
+	return bindings
+}
+
+// ParseBindings parses the chord to actions map of the configuration.
+// Two chords naming the same key, such as "ctrl-a" and "ctrl+a", conflict.
+func ParseBindings(keys map[string]string) ([]Binding, error) {
+	chords := make([]string, 0, len(keys))
+	for chord := range keys {
+		chords = append(chords, chord)
+	}
+	sort.Strings(chords)
+
+	bindings := make([]Binding, 0, len(keys))
+	seen := make(map[string]string, len(keys))
+	for _, chord := range chords {
+		key, err := ParseKey(chord)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("conflicting key bindings: %q and %q are the same key", other, chord)
+		}
+		seen[key] = chord
+
+		actions, err := ParseActions(keys[chord])
+		if err != nil {
+			return nil, fmt.Errorf("invalid binding for %q: %w", chord, err)
+		}
+		bindings = append(bindings, Binding{Key: key, Expr: keys[chord], Actions: actions})
+	}
+	return bindings, nil
+}
+
+// ParseBind parses the value of --bind: a comma separated list of
+// chord:actions pairs.
+func ParseBind(spec string) ([]Binding, error) {
+	var bindings []Binding
+	for _, part := range splitBindings(spec) {
+		sep := strings.IndexByte(part[1:], ':') + 1
+		if sep == 0 {
+			return nil, fmt.Errorf("invalid binding %q: expected chord:action", part)
+		}
+		key, err := ParseKey(part[:sep])
+		if err != nil {
+			return nil, err
+		}
+		expr := part[sep+1:]
+		actions, err := ParseActions(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid binding for %q: %w", part[:sep], err)
+		}
+		bindings = append(bindings, Binding{Key: key, Expr: expr, Actions: actions})
+	}
+	return bindings, nil
+}
+
+// splitBindings splits spec on the commas outside of action arguments. The
+// first character of a binding always belongs to the chord, so "," and ":"
+// can be bound too.
+func splitBindings(spec string) []string {
+	var parts []string
+	start := 0
+	var closer byte
+	chordDone := false
+	for i := 0; i < len(spec); i++ {
+		c := spec[i]
+		switch {
+		case i == start:
+		case closer != 0:
+			if c == closer {
+				closer = 0
+			}
+		case c == ':' && !chordDone:
+			chordDone = true
+		case c == ':':
+			// execute:cmd takes the rest of the spec.
+			return append(parts, spec[start:])
+		case closers[c] != 0:
+			closer = closers[c]
+		case c == ',':
+			parts = append(parts, spec[start:i])
+			start = i + 1
+			chordDone = false
+		}
+	}
+	if start < len(spec) {
+		parts = append(parts, spec[start:])
+	}
+	return parts
+}
+
+var closers = map[byte]byte{'(': ')', '[': ']', '{': '}'}
+
+// ParseActions parses a chain of actions joined by "+". The argument of
+// execute is given in brackets, execute(cmd), or after a colon,
+// execute:cmd, in which case it extends to the end of expr.
+func ParseActions(expr string) ([]Action, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("no action")
+	}
+
+	var actions []Action
+	for expr != "" {
+		end := strings.IndexAny(expr, "+([{:")
+		if end < 0 {
+			end = len(expr)
+		}
+		action := Action{Name: strings.TrimSpace(expr[:end])}
+		expr = expr[end:]
+
+		hasArg := false
+		if expr != "" {
+			switch c := expr[0]; c {
+			case ':':
+				action.Arg, expr, hasArg = expr[1:], "", true
+			case '(', '[', '{':
+				closing := strings.IndexByte(expr, closers[c])
+				if closing < 0 {
+					return nil, fmt.Errorf("unterminated argument of %s", action.Name)
+				}
+				action.Arg, expr, hasArg = expr[1:closing], expr[closing+1:], true
+			}
+		}
+		if expr != "" {
+			if expr[0] != '+' {
+				return nil, fmt.Errorf("unexpected %q after %s", expr, action.Name)
+			}
+			expr = expr[1:]
+			if expr == "" {
+				return nil, fmt.Errorf("missing action after %s", action.Name)
+			}
+		}
+
+		if _, ok := Actions[action.Name]; !ok {
+			return nil, fmt.Errorf("unknown action: %q", action.Name)
+		}
+		if action.Name == Execute && strings.TrimSpace(action.Arg) == "" {
+			return nil, fmt.Errorf("execute requires a command")
+		}
+		if action.Name != Execute && hasArg {
+			return nil, fmt.Errorf("action %s takes no argument", action.Name)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
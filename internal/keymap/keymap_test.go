@@ -0,0 +1,150 @@
+package keymap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		chord    string
+		expected string
+		wantErr  bool
+	}{
+		{chord: "a", expected: "a"},
+		{chord: "?", expected: "?"},
+		{chord: "-", expected: "-"},
+		{chord: "ctrl-a", expected: "ctrl+a"},
+		{chord: "ctrl+a", expected: "ctrl+a"},
+		{chord: "CTRL-A", expected: "ctrl+a"},
+		{chord: "alt-enter", expected: "alt+enter"},
+		{chord: "alt-ctrl-x", expected: "alt+ctrl+x"},
+		{chord: "ctrl-alt-x", expected: "alt+ctrl+x"},
+		{chord: "alt--", expected: "alt+-"},
+		{chord: "ctrl-space", expected: "ctrl+@"},
+		{chord: "space", expected: " "},
+		{chord: "bspace", expected: "backspace"},
+		{chord: "btab", expected: "shift+tab"},
+		{chord: "shift-up", expected: "shift+up"},
+		{chord: "ctrl-shift-up", expected: "ctrl+shift+up"},
+		{chord: "page-down", expected: "pgdown"},
+		{chord: "f12", expected: "f12"},
+		{chord: "ctrl-enter", wantErr: true},
+		{chord: "shift-a", wantErr: true},
+		{chord: "hyper-a", wantErr: true},
+		{chord: "double-click", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.chord, func(t *testing.T) {
+			key, err := ParseKey(tc.chord)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, obtained key %q", key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if key != tc.expected {
+				t.Errorf("Expected %q, obtained %q", tc.expected, key)
+			}
+		})
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		expected []Action
+		wantErr  bool
+	}{
+		{expr: "accept", expected: []Action{{Name: Accept}}},
+		{expr: "toggle-mark+down", expected: []Action{{Name: ToggleMark}, {Name: Down}}},
+		{expr: "execute(vim {})", expected: []Action{{Name: Execute, Arg: "vim {}"}}},
+		{expr: "execute[echo a+b]+accept", expected: []Action{{Name: Execute, Arg: "echo a+b"}, {Name: Accept}}},
+		{expr: "execute:less {}+x", expected: []Action{{Name: Execute, Arg: "less {}+x"}}},
+		{expr: "", wantErr: true},
+		{expr: "explode", wantErr: true},
+		{expr: "accept+", wantErr: true},
+		{expr: "execute", wantErr: true},
+		{expr: "execute()", wantErr: true},
+		{expr: "execute(vim", wantErr: true},
+		{expr: "accept(now)", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			actions, err := ParseActions(tc.expr)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, obtained %v", actions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actions, tc.expected) {
+				t.Errorf("Expected %v, obtained %v", tc.expected, actions)
+			}
+		})
+	}
+}
+
+func TestParseBind(t *testing.T) {
+	bindings, err := ParseBind("ctrl-y:copy-path,ctrl-o:execute(vim {1,2}),,:accept,alt-x:execute:echo a,b")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Binding{
+		{Key: "ctrl+y", Expr: "copy-path", Actions: []Action{{Name: CopyPath}}},
+		{Key: "ctrl+o", Expr: "execute(vim {1,2})", Actions: []Action{{Name: Execute, Arg: "vim {1,2}"}}},
+		{Key: ",", Expr: "accept", Actions: []Action{{Name: Accept}}},
+		{Key: "alt+x", Expr: "execute:echo a,b", Actions: []Action{{Name: Execute, Arg: "echo a,b"}}},
+	}
+	if !reflect.DeepEqual(bindings, expected) {
+		t.Errorf("Expected %+v, obtained %+v", expected, bindings)
+	}
+
+	for _, spec := range []string{"ctrl-y", "ctrl-y:", "ctrl-y:nothing", "hyper-y:accept"} {
+		if _, err := ParseBind(spec); err == nil {
+			t.Errorf("Expected an error for %q, got none", spec)
+		}
+	}
+}
+
+func TestParseBindings(t *testing.T) {
+	bindings, err := ParseBindings(map[string]string{"ctrl-y": "copy-path", "f1": "help"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(bindings) != 2 || bindings[0].Key != "ctrl+y" || bindings[1].Key != "f1" {
+		t.Errorf("Unexpected bindings %+v", bindings)
+	}
+
+	if _, err := ParseBindings(map[string]string{"ctrl-a": "accept", "ctrl+a": "abort"}); err == nil {
+		t.Error("Expected an error for conflicting chords, got none")
+	}
+	if _, err := ParseBindings(map[string]string{"ctrl-a": "launch"}); err == nil {
+		t.Error("Expected an error for an unknown action, got none")
+	}
+}
+
+func TestKeymapBind(t *testing.T) {
+	k := Default()
+	k.Bind([]Binding{{Key: "enter", Actions: []Action{{Name: Ignore}}}})
+	if !reflect.DeepEqual(k["enter"], []Action{{Name: Ignore}}) {
+		t.Errorf("Expected enter to be rebound, obtained %v", k["enter"])
+	}
+
+	for _, b := range Default().Bindings() {
+		for _, a := range b.Actions {
+			if _, ok := Actions[a.Name]; !ok {
+				t.Errorf("Default binding %q uses unknown action %q", b.Key, a.Name)
+			}
+		}
+	}
+}
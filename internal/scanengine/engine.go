@@ -12,6 +12,10 @@ type Config struct {
 	Root       string
 	NumWorkers int
 	FindIgnore *findingnore.FindIgnore
+	// Roots are scanned instead of Root when set.
+	Roots []string
+	// Dirs makes the scanner report directories instead of files.
+	Dirs bool
 }
 
 type Scanner struct {
@@ -44,8 +48,14 @@ func (s *Scanner) Run() <-chan string {
 		}()
 	}
 
-	s.taskWg.Add(1)
-	s.workQueue <- s.config.Root
+	roots := s.config.Roots
+	if len(roots) == 0 {
+		roots = []string{s.config.Root}
+	}
+	s.taskWg.Add(len(roots))
+	for _, root := range roots {
+		s.workQueue <- root
+	}
 
 	go func() {
 		s.workerWg.Wait()
@@ -91,11 +101,14 @@ func (s *Scanner) scan(path string) {
 		}
 
 		if entry.IsDir() {
+			if s.config.Dirs {
+				s.resultsQueue <- fullPath
+			}
 			s.taskWg.Add(1)
 			go func(p string) {
 				s.workQueue <- p
 			}(fullPath)
-		} else {
+		} else if !s.config.Dirs {
 			if fileInfo, err := os.Stat(fullPath); err == nil && !fileInfo.IsDir() {
 				s.resultsQueue <- fullPath
 			}
@@ -4,6 +4,7 @@ import (
 	findignore "jetfind/internal/findignore"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"testing"
 )
@@ -120,6 +121,37 @@ func TestBasicScan(t *testing.T) {
 	}
 }
 
+func TestScanDirs(t *testing.T) {
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	config := Config{
+		Root:       root,
+		NumWorkers: 2,
+		Dirs:       true,
+	}
+	scanner := New(config)
+	results := collectResults(scanner.Run())
+
+	expected := []string{
+		filepath.Join(root, ".git"),
+		filepath.Join(root, "ignored_dir"),
+		filepath.Join(root, "sub"),
+		filepath.Join(root, "sub", "nested"),
+	}
+	sort.Strings(expected)
+
+	if len(results) != len(expected) {
+		t.Fatalf("Wrong number of results. Expected: %d, Got: %d\nExpected: %v\nGot: %v", len(expected), len(results), expected, results)
+	}
+
+	for i := range results {
+		if results[i] != expected[i] {
+			t.Errorf("Unexpected results at %d. Expected: %s, Got: %s", i, expected[i], results[i])
+		}
+	}
+}
+
 func TestScanWithFindIgnore(t *testing.T) {
 	root, cleanup := createTestDir(t)
 	defer cleanup()
@@ -157,3 +189,25 @@ func TestScanWithFindIgnore(t *testing.T) {
 		}
 	}
 }
+
+func TestScanRoots(t *testing.T) {
+	root, cleanup := createTestDir(t)
+	defer cleanup()
+
+	// The nested root is already part of sub and is scanned once.
+	config := Config{
+		Root:       root,
+		Roots:      []string{filepath.Join(root, "sub"), filepath.Join(root, "ignored_dir"), filepath.Join(root, "sub", "nested")},
+		NumWorkers: 2,
+	}
+	results := collectResults(New(config).Run())
+
+	expected := []string{
+		filepath.Join(root, "ignored_dir", "ignored_file.txt"),
+		filepath.Join(root, "sub", "file2.go"),
+		filepath.Join(root, "sub", "nested", "file3.md"),
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected %v, got %v", expected, results)
+	}
+}
@@ -1,8 +1,8 @@
 package scanengine
 
 import (
-	"path/filepath"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -11,6 +11,61 @@ const (
 	AlgoLevenshtein = "levenshtein"
 )
 
+var Algos = []string{AlgoJaroWinkler, AlgoNGram, AlgoLevenshtein}
+
+const (
+	TypeContains = "contains"
+	TypeFuzzy    = "fuzzy"
+)
+
+var FilterTypes = []string{TypeFuzzy, TypeContains}
+
+// FilterMode is a filter type and, for the fuzzy filter, its algorithm.
+type FilterMode struct {
+	Type string
+	Algo string
+}
+
+func (m FilterMode) String() string {
+	if m.Type == TypeFuzzy {
+		return m.Type + "/" + m.Algo
+	}
+	return m.Type
+}
+
+// FilterModes lists the contains filter, then the fuzzy filter with each
+// of Algos.
+func FilterModes() []FilterMode {
+	modes := []FilterMode{{Type: TypeContains}}
+	for _, algo := range Algos {
+		modes = append(modes, FilterMode{Type: TypeFuzzy, Algo: algo})
+	}
+	return modes
+}
+
+const (
+	CaseSmart   = "smart"
+	CaseIgnore  = "ignore"
+	CaseRespect = "respect"
+)
+
+var CaseModes = []string{CaseSmart, CaseIgnore, CaseRespect}
+
+// applyCase prepares text and pattern for a comparison in the given case
+// mode. Smart case ignores case unless the pattern contains an uppercase
+// letter. An empty mode ignores case.
+func applyCase(mode, text, pattern string) (string, string) {
+	switch mode {
+	case CaseRespect:
+		return text, pattern
+	case CaseSmart:
+		if strings.IndexFunc(pattern, unicode.IsUpper) >= 0 {
+			return text, pattern
+		}
+	}
+	return strings.ToLower(text), strings.ToLower(pattern)
+}
+
 type ScanFilteredResult struct {
 	Path  string
 	Score float64
@@ -28,14 +83,53 @@ type ExactFilter struct {
 	Pattern string
 }
 
+// ContainsFilter and FuzzyFilter normalize the paths with Normalizer. Their
+// Pattern is normalized once, by NewScanFilter.
 type ContainsFilter struct {
-	Pattern string
+	Pattern    string
+	Case       string
+	Normalizer Normalizer
+	Scope      Scope
 }
 
 type FuzzyFilter struct {
 	Pattern    string
 	Threashold float64
 	Algo       string
+	Case       string
+	Normalizer Normalizer
+	Scope      Scope
+}
+
+// FilterOptions describes how the filter built by NewScanFilter matches a
+// pattern.
+type FilterOptions struct {
+	Type       string
+	Algo       string
+	Threshold  float64
+	Case       string
+	Normalizer Normalizer
+	Scope      Scope
+}
+
+// NewScanFilter builds the filter matching the configured filter type.
+// Unknown types fall back to NoFilter.
+func NewScanFilter(opts FilterOptions, pattern string) ScanFilter {
+	switch opts.Type {
+	case TypeContains:
+		return ContainsFilter{Pattern: opts.Normalizer.Normalize(pattern), Case: opts.Case, Normalizer: opts.Normalizer, Scope: opts.Scope}
+	case TypeFuzzy:
+		return FuzzyFilter{
+			Pattern:    opts.Normalizer.Normalize(pattern),
+			Algo:       opts.Algo,
+			Threashold: opts.Threshold,
+			Case:       opts.Case,
+			Normalizer: opts.Normalizer,
+			Scope:      opts.Scope,
+		}
+	default:
+		return NoFilter{Pattern: pattern}
+	}
 }
 
 func (nf NoFilter) Apply(path string) (ScanFilteredResult, bool) {
@@ -50,33 +144,33 @@ func (ef ExactFilter) Apply(path string) (ScanFilteredResult, bool) {
 }
 
 func (cf ContainsFilter) Apply(path string) (ScanFilteredResult, bool) {
-	if strings.Contains(strings.ToLower(path), strings.ToLower(cf.Pattern)) {
+	scoped, _ := cf.Scope.text(path, cf.Pattern)
+	text, pattern := applyCase(cf.Case, cf.Normalizer.Normalize(scoped), cf.Pattern)
+	if strings.Contains(text, pattern) {
 		return ScanFilteredResult{Path: path, Score: 1.0}, true
 	}
 	return ScanFilteredResult{}, false
 }
 
 func (ff FuzzyFilter) Apply(path string) (ScanFilteredResult, bool) {
+	scoped, _ := ff.Scope.text(path, ff.Pattern)
+	text, pattern := applyCase(ff.Case, ff.Normalizer.Normalize(scoped), ff.Pattern)
 	if ff.Algo == AlgoNGram {
-		pathNgram := createNgram(path, 2)
-		patternNgram := createNgram(ff.Pattern, 2)
+		pathNgram := createNgram(text, 2)
+		patternNgram := createNgram(pattern, 2)
 		overlap := getOverlapCoefficient(pathNgram, patternNgram)
 		if overlap > ff.Threashold {
 			return ScanFilteredResult{Path: path, Score: overlap}, true
 		}
 		return ScanFilteredResult{}, false
 	} else if ff.Algo == AlgoJaroWinkler {
-		slashedPath := filepath.ToSlash(path)
-		parts := strings.Split(slashedPath, "/")
-		jwSim := jaroWinkler(parts[len(parts)-1], ff.Pattern)
+		jwSim := jaroWinkler(text, pattern)
 		if jwSim > ff.Threashold {
 			return ScanFilteredResult{Path: path, Score: jwSim}, true
 		}
 		return ScanFilteredResult{}, false
 	} else if ff.Algo == AlgoLevenshtein {
-		slashedPath := filepath.ToSlash(path)
-		parts := strings.Split(slashedPath, "/")
-		levSim := levenshteinSimilarity(parts[len(parts)-1], ff.Pattern)
+		levSim := levenshteinSimilarity(text, pattern)
 		if levSim > ff.Threashold {
 			return ScanFilteredResult{Path: path, Score: levSim}, true
 		}
@@ -127,7 +127,7 @@ func TestFuzzyFilter(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			filter := FuzzyFilter{Pattern: tc.pattern, Threashold: tc.threshold, Algo: AlgoJaroWinkler}
+			filter := FuzzyFilter{Pattern: tc.pattern, Threashold: tc.threshold, Algo: AlgoJaroWinkler, Scope: Scope{Mode: ScopeBasename}}
 			_, result := filter.Apply(tc.path)
 			if result != tc.expected {
 				t.Errorf("Expected %v, but obtained %v for path '%s' with pattern '%s'", tc.expected, result, tc.path, tc.pattern)
@@ -135,3 +135,104 @@ func TestFuzzyFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizer(t *testing.T) {
+	const composed = "café"
+	const decomposed = "cafe\u0301"
+
+	testCases := []struct {
+		name       string
+		normalizer Normalizer
+		input      string
+		expected   string
+	}{
+		{name: "none keeps input", normalizer: Normalizer{}, input: decomposed, expected: decomposed},
+		{name: "nfc composes", normalizer: Normalizer{Form: NormNFC}, input: decomposed, expected: composed},
+		{name: "nfd decomposes", normalizer: Normalizer{Form: NormNFD}, input: composed, expected: decomposed},
+		{name: "fold composed", normalizer: Normalizer{FoldDiacritics: true}, input: composed, expected: "cafe"},
+		{name: "fold decomposed", normalizer: Normalizer{FoldDiacritics: true}, input: decomposed, expected: "cafe"},
+		{name: "fold keeps CJK", normalizer: Normalizer{FoldDiacritics: true}, input: "東京", expected: "東京"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.normalizer.Normalize(tc.input); got != tc.expected {
+				t.Errorf("Normalize(%q) = %q, expected %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFilterDiacriticFolding(t *testing.T) {
+	const path = "/home/user/docs/café.txt"
+	fold := Normalizer{FoldDiacritics: true}
+
+	testCases := []struct {
+		name     string
+		filter   ScanFilter
+		expected bool
+	}{
+		{name: "contains without folding", filter: ContainsFilter{Pattern: "cafe"}, expected: false},
+		{name: "contains with folding", filter: ContainsFilter{Pattern: "cafe", Normalizer: fold}, expected: true},
+		{name: "jarowinkler with folding", filter: FuzzyFilter{Pattern: "cafe.txt", Algo: AlgoJaroWinkler, Threashold: 0.99, Normalizer: fold, Scope: Scope{Mode: ScopeBasename}}, expected: true},
+		{name: "levenshtein with folding", filter: FuzzyFilter{Pattern: "cafe.txt", Algo: AlgoLevenshtein, Threashold: 0.99, Normalizer: fold, Scope: Scope{Mode: ScopeBasename}}, expected: true},
+		{name: "ngram nfc vs nfd", filter: NewScanFilter(FilterOptions{Type: TypeFuzzy, Algo: AlgoNGram, Threshold: 0.99, Normalizer: Normalizer{Form: NormNFC}}, "cafe\u0301"), expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, result := tc.filter.Apply(path)
+			if result != tc.expected {
+				t.Errorf("Expected %v, but obtained %v for path '%s'", tc.expected, result, path)
+			}
+			if result && res.Path != path {
+				t.Errorf("Expected the original path '%s', obtained '%s'", path, res.Path)
+			}
+		})
+	}
+}
+
+func TestFilterCaseModes(t *testing.T) {
+	const path = "/home/user/project/README.md"
+
+	filters := map[string]func(pattern, mode string) ScanFilter{
+		"contains": func(pattern, mode string) ScanFilter {
+			return ContainsFilter{Pattern: pattern, Case: mode}
+		},
+		"jarowinkler": func(pattern, mode string) ScanFilter {
+			return FuzzyFilter{Pattern: pattern, Case: mode, Algo: AlgoJaroWinkler, Threashold: 0.99, Scope: Scope{Mode: ScopeBasename}}
+		},
+		"levenshtein": func(pattern, mode string) ScanFilter {
+			return FuzzyFilter{Pattern: pattern, Case: mode, Algo: AlgoLevenshtein, Threashold: 0.99, Scope: Scope{Mode: ScopeBasename}}
+		},
+		"ngram": func(pattern, mode string) ScanFilter {
+			return FuzzyFilter{Pattern: pattern, Case: mode, Algo: AlgoNGram, Threashold: 0.99}
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		mode     string
+		expected bool
+	}{
+		{name: "ignore lowercase pattern", pattern: "readme.md", mode: CaseIgnore, expected: true},
+		{name: "ignore mixed case pattern", pattern: "ReadMe.md", mode: CaseIgnore, expected: true},
+		{name: "respect lowercase pattern", pattern: "readme.md", mode: CaseRespect, expected: false},
+		{name: "respect exact case", pattern: "README.md", mode: CaseRespect, expected: true},
+		{name: "smart lowercase pattern", pattern: "readme.md", mode: CaseSmart, expected: true},
+		{name: "smart uppercase pattern", pattern: "ReadMe.md", mode: CaseSmart, expected: false},
+		{name: "smart exact case", pattern: "README.md", mode: CaseSmart, expected: true},
+	}
+
+	for filterName, newFilter := range filters {
+		for _, tc := range testCases {
+			t.Run(filterName+"/"+tc.name, func(t *testing.T) {
+				_, result := newFilter(tc.pattern, tc.mode).Apply(path)
+				if result != tc.expected {
+					t.Errorf("Expected %v, but obtained %v for path '%s' with pattern '%s'", tc.expected, result, path, tc.pattern)
+				}
+			})
+		}
+	}
+}
@@ -1,77 +1,193 @@
 package scanengine
 
 import (
+	"container/heap"
+	"context"
 	"math"
 	"runtime"
 	"sort"
 	"sync"
 )
 
-func FilterEngine(pathBuffer []ScanFilteredResult, scanFilter ScanFilter) []ScanFilteredResult {
-	if len(pathBuffer) == 0 {
-		return make([]ScanFilteredResult, 0)
-	}
+// Number of paths a worker processes between two checks of the context.
+const ctxCheckInterval = 256
 
-	const minPathsForParallel = 100
-	if len(pathBuffer) < minPathsForParallel {
-		filteredResults := make([]ScanFilteredResult, 0, len(pathBuffer)/4)
-		for _, path := range pathBuffer {
-			if p, filtered := scanFilter.Apply(path.Path); filtered {
-				filteredResults = append(filteredResults, p)
-			}
-		}
-		return filteredResults
+const minPathsForParallel = 100
+
+// resultHeap keeps the worst retained result at the root, so it can be
+// evicted in O(log k) when a better one shows up.
+type resultHeap struct {
+	items []rankedResult
+	order SortOrder
+}
+
+func (h *resultHeap) Len() int           { return len(h.items) }
+func (h *resultHeap) Less(i, j int) bool { return h.order.less(&h.items[j], &h.items[i]) }
+func (h *resultHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *resultHeap) Push(x any)         { h.items = append(h.items, x.(rankedResult)) }
+func (h *resultHeap) Pop() any {
+	n := len(h.items)
+	x := h.items[n-1]
+	h.items = h.items[:n-1]
+	return x
+}
+
+func (h *resultHeap) offer(r rankedResult, k int) {
+	if h.Len() < k {
+		heap.Push(h, r)
+	} else if h.order.less(&r, &h.items[0]) {
+		h.items[0] = r
+		heap.Fix(h, 0)
 	}
+}
 
-	var wg sync.WaitGroup
+func sortRanked(results []rankedResult, order SortOrder) []ScanFilteredResult {
+	sort.Slice(results, func(i, j int) bool {
+		return order.less(&results[i], &results[j])
+	})
+
+	sorted := make([]ScanFilteredResult, len(results))
+	for i, r := range results {
+		sorted[i] = r.ScanFilteredResult
+	}
+	return sorted
+}
 
-	filteredResults := make([]ScanFilteredResult, 0)
-	filteredResultsChan := make(chan ScanFilteredResult, runtime.NumCPU())
+func splitWork(n int) [][]int {
+	if n < minPathsForParallel {
+		return [][]int{{0, n}}
+	}
 
-	pathsPerWorker := len(pathBuffer)
+	pathsPerWorker := n
 
 	numWorkers := runtime.NumCPU()
-	if len(pathBuffer) > numWorkers*10 {
-		pathsPerWorker = int(math.Floor(float64(len(pathBuffer)) / float64(numWorkers)))
+	if n > numWorkers*10 {
+		pathsPerWorker = int(math.Floor(float64(n) / float64(numWorkers)))
 	}
 
+	chunks := make([][]int, 0, numWorkers)
 	for i := range numWorkers {
 		start := i * pathsPerWorker
 		end := start + pathsPerWorker
 
-		if start > len(pathBuffer) {
+		if start >= n {
 			break
 		}
 
-		if end > len(pathBuffer) {
-			end = len(pathBuffer)
+		if end > n || i == numWorkers-1 {
+			end = n
 		}
+		chunks = append(chunks, []int{start, end})
+	}
+	return chunks
+}
 
+// FilterEngine returns every path of pathBuffer accepted by scanFilter,
+// ranked by ranking (DefaultSortOrder when no order is given).
+func FilterEngine(ctx context.Context, pathBuffer []ScanFilteredResult, scanFilter ScanFilter, ranking Ranking) ([]ScanFilteredResult, error) {
+	if len(pathBuffer) == 0 {
+		return make([]ScanFilteredResult, 0), nil
+	}
+
+	order := ranking.Order.orDefault()
+	withModTime := order.needsModTime()
+
+	var wg sync.WaitGroup
+
+	chunks := splitWork(len(pathBuffer))
+	partials := make([][]rankedResult, len(chunks))
+
+	for i, chunk := range chunks {
 		wg.Add(1)
-		go func(paths []ScanFilteredResult) {
+		go func(i int, start int, paths []ScanFilteredResult) {
 			defer wg.Done()
-			for _, path := range paths {
-				p, filtered := scanFilter.Apply(path.Path)
-				if filtered {
-					filteredResultsChan <- p
+			partial := make([]rankedResult, 0)
+			for j, path := range paths {
+				if j%ctxCheckInterval == 0 && ctx.Err() != nil {
+					return
+				}
+				if p, filtered := scanFilter.Apply(path.Path); filtered {
+					partial = append(partial, newRankedResult(ranking.apply(p), start+j, withModTime))
 				}
 			}
+			partials[i] = partial
+		}(i, chunk[0], pathBuffer[chunk[0]:chunk[1]])
+	}
+	wg.Wait()
 
-		}(pathBuffer[start:end])
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	go func() {
-		wg.Wait()
-		close(filteredResultsChan)
-	}()
+	total := 0
+	for _, partial := range partials {
+		total += len(partial)
+	}
+	filteredResults := make([]rankedResult, 0, total)
+	for _, partial := range partials {
+		filteredResults = append(filteredResults, partial...)
+	}
 
-	for filteredPath := range filteredResultsChan {
-		filteredResults = append(filteredResults, filteredPath)
+	return sortRanked(filteredResults, order), nil
+}
+
+// FilterEngineTopK returns the k best matches, ranked by ranking, together
+// with the total number of matches. Each worker keeps a bounded heap of its
+// own best k results, so memory and sorting cost depend on k rather than on
+// the number of matches. A non-positive k falls back to FilterEngine.
+func FilterEngineTopK(ctx context.Context, pathBuffer []ScanFilteredResult, scanFilter ScanFilter, ranking Ranking, k int) ([]ScanFilteredResult, int, error) {
+	if k <= 0 {
+		results, err := FilterEngine(ctx, pathBuffer, scanFilter, ranking)
+		return results, len(results), err
 	}
 
-	sort.Slice(filteredResults, func(i, j int) bool {
-		return filteredResults[i].Score > filteredResults[j].Score
-	})
+	if len(pathBuffer) == 0 {
+		return make([]ScanFilteredResult, 0), 0, nil
+	}
+
+	order := ranking.Order.orDefault()
+	withModTime := order.needsModTime()
+
+	var wg sync.WaitGroup
+
+	chunks := splitWork(len(pathBuffer))
+	heaps := make([]*resultHeap, len(chunks))
+	counts := make([]int, len(chunks))
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, start int, paths []ScanFilteredResult) {
+			defer wg.Done()
+			h := &resultHeap{items: make([]rankedResult, 0, k), order: order}
+			for j, path := range paths {
+				if j%ctxCheckInterval == 0 && ctx.Err() != nil {
+					return
+				}
+				if p, filtered := scanFilter.Apply(path.Path); filtered {
+					counts[i]++
+					h.offer(newRankedResult(ranking.apply(p), start+j, withModTime), k)
+				}
+			}
+			heaps[i] = h
+		}(i, chunk[0], pathBuffer[chunk[0]:chunk[1]])
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	merged := make([]rankedResult, 0, k*len(heaps))
+	for i, h := range heaps {
+		total += counts[i]
+		merged = append(merged, h.items...)
+	}
+
+	results := sortRanked(merged, order)
+	if len(results) > k {
+		results = results[:k]
+	}
 
-	return filteredResults
+	return results, total, nil
 }
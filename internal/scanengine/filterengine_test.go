@@ -1,6 +1,8 @@
 package scanengine
 
 import (
+	"context"
+	"fmt"
 	"testing"
 )
 
@@ -56,7 +58,10 @@ func TestBasicFilterEngine(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		res := FilterEngine(tc.pathBuffer, tc.scanFilter)
+		res, err := FilterEngine(context.Background(), tc.pathBuffer, tc.scanFilter, Ranking{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		if len(res) != len(tc.expected) {
 			t.Errorf("The length of the results is wrong. Expected %v; Obtained %v", len(tc.expected), len(res))
@@ -69,3 +74,151 @@ func TestBasicFilterEngine(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterEngineAllPathsProcessed(t *testing.T) {
+	pathBuffer := make([]ScanFilteredResult, 0, 1001)
+	for i := range 1001 {
+		pathBuffer = append(pathBuffer, ScanFilteredResult{Path: fmt.Sprintf("root/file%d", i), Score: 1.0})
+	}
+
+	res, err := FilterEngine(context.Background(), pathBuffer, NoFilter{}, Ranking{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res) != len(pathBuffer) {
+		t.Errorf("Expected %d results, obtained %d", len(pathBuffer), len(res))
+	}
+}
+
+func TestFilterEngineCancelled(t *testing.T) {
+	pathBuffer := make([]ScanFilteredResult, 0, 1000)
+	for i := range 1000 {
+		pathBuffer = append(pathBuffer, ScanFilteredResult{Path: fmt.Sprintf("root/file%d", i), Score: 1.0})
+	}
+
+	for _, size := range []int{10, len(pathBuffer)} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res, err := FilterEngine(ctx, pathBuffer[:size], NoFilter{}, Ranking{})
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled with %d paths, obtained %v", size, err)
+		}
+		if res != nil {
+			t.Errorf("Expected nil results with %d paths, obtained %d results", size, len(res))
+		}
+	}
+}
+
+func TestFilterEngineTopK(t *testing.T) {
+	for _, size := range []int{50, 5000} {
+		pathBuffer := make([]ScanFilteredResult, 0, size)
+		for i := range size {
+			pathBuffer = append(pathBuffer, ScanFilteredResult{Path: fmt.Sprintf("root/file%d", i)})
+		}
+		scanFilter := FuzzyFilter{Pattern: "file1", Algo: AlgoLevenshtein, Threashold: 0.0}
+
+		all, err := FilterEngine(context.Background(), pathBuffer, scanFilter, Ranking{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		const k = 10
+		top, total, err := FilterEngineTopK(context.Background(), pathBuffer, scanFilter, Ranking{}, k)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if total != len(all) {
+			t.Errorf("Size %d: expected total %d, obtained %d", size, len(all), total)
+		}
+		if len(top) != k {
+			t.Fatalf("Size %d: expected %d results, obtained %d", size, k, len(top))
+		}
+		for i := range top {
+			if top[i] != all[i] {
+				t.Errorf("Size %d: mismatch at %d. Expected %v; Obtained %v", size, i, all[i], top[i])
+			}
+		}
+	}
+}
+
+func TestFilterEngineTopKNoLimit(t *testing.T) {
+	pathBuffer := []ScanFilteredResult{
+		{Path: "root/parent/sub1/file1", Score: 1.0},
+		{Path: "root/parent/sub2/file2", Score: 1.0},
+	}
+
+	res, total, err := FilterEngineTopK(context.Background(), pathBuffer, ContainsFilter{Pattern: "root"}, Ranking{}, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(res) != 2 || total != 2 {
+		t.Errorf("Expected 2 results and total 2, obtained %d results and total %d", len(res), total)
+	}
+}
+
+func syntheticPaths(n int) []ScanFilteredResult {
+	dirs := []string{"src", "internal", "pkg", "cmd", "docs", "test", "vendor", "build"}
+	paths := make([]ScanFilteredResult, 0, n)
+	for i := range n {
+		p := fmt.Sprintf("/home/user/project/%s/module%d/%s/file_%d.go", dirs[i%len(dirs)], i%97, dirs[(i/8)%len(dirs)], i)
+		paths = append(paths, ScanFilteredResult{Path: p, Score: 1.0})
+	}
+	return paths
+}
+
+func BenchmarkFilterEngine(b *testing.B) {
+	pathBuffer := syntheticPaths(1_000_000)
+	scanFilter := ContainsFilter{Pattern: "file_1"}
+	for b.Loop() {
+		if _, err := FilterEngine(context.Background(), pathBuffer, scanFilter, Ranking{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFilterEngineTopK(b *testing.B) {
+	pathBuffer := syntheticPaths(1_000_000)
+	scanFilter := ContainsFilter{Pattern: "file_1"}
+	for b.Loop() {
+		if _, _, err := FilterEngineTopK(context.Background(), pathBuffer, scanFilter, Ranking{}, 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFilterEngineBoost(t *testing.T) {
+	pathBuffer := []ScanFilteredResult{
+		{Path: "root/a.go"},
+		{Path: "root/b.go"},
+		{Path: "root/c.go"},
+	}
+	ranking := Ranking{
+		Order: SortOrder{TiebreakScore, TiebreakIndex},
+		Boost: func(path string) float64 {
+			if path == "root/c.go" {
+				return 1.0
+			}
+			return 0.0
+		},
+		BoostWeight: 0.5,
+	}
+
+	res, err := FilterEngine(context.Background(), pathBuffer, NoFilter{}, ranking)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res[0].Path != "root/c.go" || res[0].Score != 1.0 {
+		t.Errorf("Expected boosted root/c.go first with score 1.0, obtained %v", res[0])
+	}
+	if res[1].Score != 0.5 {
+		t.Errorf("Expected blended score 0.5, obtained %v", res[1].Score)
+	}
+
+	ranking.BoostWeight = 0
+	res, _ = FilterEngine(context.Background(), pathBuffer, NoFilter{}, ranking)
+	if res[0].Path != "root/a.go" {
+		t.Errorf("Expected no boost with a zero weight, obtained %v", res[0])
+	}
+}
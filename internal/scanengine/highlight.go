@@ -0,0 +1,67 @@
+package scanengine
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MatchPositions returns the indexes of the runes of path to highlight for
+// pattern: the first occurrence of pattern for the contains filter, and the
+// runes of pattern found in order for the fuzzy one. The fuzzy filters
+// score similarity rather than locate the pattern, so the positions are an
+// approximation, and nil when the runes are not all found. Only the part of
+// path in the scope of opts is searched.
+func MatchPositions(opts FilterOptions, path, pattern string) []int {
+	scoped, offset := opts.Scope.text(path, pattern)
+	text, runes := []rune(scoped), []rune(pattern)
+	if len(runes) == 0 {
+		return nil
+	}
+	fold := opts.Case != CaseRespect && (opts.Case != CaseSmart || strings.IndexFunc(pattern, unicode.IsUpper) < 0)
+	equal := func(a, b rune) bool {
+		return a == b || fold && unicode.ToLower(a) == unicode.ToLower(b)
+	}
+
+	switch opts.Type {
+	case TypeContains:
+		for start := 0; start+len(runes) <= len(text); start++ {
+			found := true
+			for i, r := range runes {
+				if !equal(text[start+i], r) {
+					found = false
+					break
+				}
+			}
+			if found {
+				positions := make([]int, len(runes))
+				for i := range positions {
+					positions[i] = start + i
+				}
+				return inPath(positions, offset)
+			}
+		}
+	case TypeFuzzy:
+		positions := make([]int, 0, len(runes))
+		for i := 0; i < len(text) && len(positions) < len(runes); i++ {
+			if equal(text[i], runes[len(positions)]) {
+				positions = append(positions, i)
+			}
+		}
+		if len(positions) == len(runes) {
+			return inPath(positions, offset)
+		}
+	}
+	return nil
+}
+
+// inPath moves positions in the scoped text to path, dropping those in a
+// prepended root.
+func inPath(positions []int, offset int) []int {
+	inPath := positions[:0]
+	for _, p := range positions {
+		if p+offset >= 0 {
+			inPath = append(inPath, p+offset)
+		}
+	}
+	return inPath
+}
@@ -0,0 +1,92 @@
+package scanengine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchPositions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     FilterOptions
+		path     string
+		pattern  string
+		expected []int
+	}{
+		{
+			name:     "Contains",
+			opts:     FilterOptions{Type: TypeContains},
+			path:     "src/main.go",
+			pattern:  "main",
+			expected: []int{4, 5, 6, 7},
+		},
+		{
+			name:     "Contains Ignoring Case",
+			opts:     FilterOptions{Type: TypeContains, Case: CaseSmart},
+			path:     "src/Main.go",
+			pattern:  "main",
+			expected: []int{4, 5, 6, 7},
+		},
+		{
+			name:    "Contains Respecting Case",
+			opts:    FilterOptions{Type: TypeContains, Case: CaseSmart},
+			path:    "src/main.go",
+			pattern: "Main",
+		},
+		{
+			name:     "Fuzzy",
+			opts:     FilterOptions{Type: TypeFuzzy},
+			path:     "src/main.go",
+			pattern:  "smg",
+			expected: []int{0, 4, 9},
+		},
+		{
+			name:     "Fuzzy Runes",
+			opts:     FilterOptions{Type: TypeFuzzy},
+			path:     "café/ÉTÉ.txt",
+			pattern:  "éé",
+			expected: []int{3, 5},
+		},
+		{
+			name:    "Fuzzy Not Found",
+			opts:    FilterOptions{Type: TypeFuzzy},
+			path:    "src/main.go",
+			pattern: "mainz",
+		},
+		{
+			name:     "Basename",
+			opts:     FilterOptions{Type: TypeFuzzy, Scope: Scope{Mode: ScopeBasename}},
+			path:     "src/main.go",
+			pattern:  "mn",
+			expected: []int{4, 7},
+		},
+		{
+			name:     "Path With The Root",
+			opts:     FilterOptions{Type: TypeContains, Scope: Scope{Mode: ScopePath, Root: "/home/src"}},
+			path:     "src/main.go",
+			pattern:  "src/m",
+			expected: []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:     "Root Dropped",
+			opts:     FilterOptions{Type: TypeContains, Scope: Scope{Mode: ScopePath, Root: "/home"}},
+			path:     "src/main.go",
+			pattern:  "me/src",
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:    "Empty Pattern",
+			opts:    FilterOptions{Type: TypeContains},
+			path:    "src/main.go",
+			pattern: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if obtained := MatchPositions(tc.opts, tc.path, tc.pattern); !reflect.DeepEqual(obtained, tc.expected) {
+				t.Errorf("Expected %v, obtained %v", tc.expected, obtained)
+			}
+		})
+	}
+}
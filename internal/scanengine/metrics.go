@@ -2,36 +2,43 @@ package scanengine
 
 import (
 	"math"
-	"strings"
 )
 
 func createNgram(str string, ngramLen int) []string {
-	if len(str) <= ngramLen {
+	runes := []rune(str)
+	if len(runes) <= ngramLen {
 		return []string{str}
 	}
 	ngrams := []string{}
-	runes := []rune(str)
 	for i := 0; i <= len(runes)-ngramLen; i++ {
 		ngrams = append(ngrams, string(runes[i:i+ngramLen]))
 	}
 	return ngrams
 }
 
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, v := range items {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
 func getOverlapCoefficient(str, pattern []string) float64 {
-	smallerSetSize := math.Min(float64(len(str)), float64(len(pattern)))
+	// Repeated ngrams are counted once, otherwise the coefficient could
+	// exceed 1.
+	strSet := toSet(str)
+	patternSet := toSet(pattern)
+
+	smallerSetSize := math.Min(float64(len(strSet)), float64(len(patternSet)))
 	if smallerSetSize == 0 {
 		return 0.0
 	}
 
-	set := make(map[string]struct{}, len(pattern))
-	for _, v := range pattern {
-		set[v] = struct{}{}
-	}
-
 	intrs := 0
 
-	for _, ngram := range str {
-		if _, ok := set[ngram]; ok {
+	for ngram := range strSet {
+		if _, ok := patternSet[ngram]; ok {
 			intrs++
 		}
 	}
@@ -39,7 +46,9 @@ func getOverlapCoefficient(str, pattern []string) float64 {
 	return float64(intrs) / smallerSetSize
 }
 
-func jaroWinkler(s1, s2 string) float64 {
+func jaroWinkler(str1, str2 string) float64 {
+	s1 := []rune(str1)
+	s2 := []rune(str2)
 	s1Len := len(s1)
 	s2Len := len(s2)
 
@@ -47,10 +56,7 @@ func jaroWinkler(s1, s2 string) float64 {
 		return 0.0
 	}
 
-	s1 = strings.ToLower(s1)
-	s2 = strings.ToLower(s2)
-
-	if s1 == s2 {
+	if string(s1) == string(s2) {
 		return 1.0
 	}
 
@@ -116,7 +122,9 @@ func jaroWinkler(s1, s2 string) float64 {
 
 }
 
-func levenshteinSimilarity(s1, s2 string) float64 {
+func levenshteinSimilarity(str1, str2 string) float64 {
+	s1 := []rune(str1)
+	s2 := []rune(str2)
 	n := len(s1)
 	m := len(s2)
 
@@ -4,6 +4,7 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"testing/quick"
 )
 
 func TestCreateNgrams(t *testing.T) {
@@ -49,6 +50,18 @@ func TestCreateNgrams(t *testing.T) {
 			ngramLen: 2,
 			expected: []string{"pr", "ro", "oj", "je", "ec", "ct"},
 		},
+		{
+			name:     "Multibyte characters shorter than ngram",
+			str:      "é",
+			ngramLen: 2,
+			expected: []string{"é"},
+		},
+		{
+			name:     "Multibyte characters",
+			str:      "café",
+			ngramLen: 2,
+			expected: []string{"ca", "af", "fé"},
+		},
 		{
 			name:     "Real case string with 3gram",
 			str:      "project",
@@ -107,6 +120,12 @@ func TestGetOverlapCoefficient(t *testing.T) {
 			pattern:  []string{"ab", "bc", "cd", "zk"},
 			expected: 1.0,
 		},
+		{
+			name:     "Repeated ngrams are counted once",
+			str:      []string{"aa", "aa", "aa"},
+			pattern:  []string{"aa"},
+			expected: 1.0,
+		},
 		{
 			name:     "Single overlap different len strings",
 			str:      []string{"ab", "bc", "cd", "zk"},
@@ -176,11 +195,29 @@ func TestJaroWinkler(t *testing.T) {
 			expected: 0.957,
 		},
 		{
-			name:     "case insensitive",
+			name:     "case sensitive",
 			str1:     "Test",
 			str2:     "test",
+			expected: 0.833,
+		},
+		{
+			name:     "accented perfect match",
+			str1:     "café",
+			str2:     "café",
 			expected: 1.0,
 		},
+		{
+			name:     "accented typo",
+			str1:     "résumé",
+			str2:     "résume",
+			expected: 0.933,
+		},
+		{
+			name:     "CJK transposition",
+			str1:     "東京都庁",
+			str2:     "東京庁都",
+			expected: 0.933,
+		},
 		{
 			name:     "spaces",
 			str1:     "a b c",
@@ -243,6 +280,18 @@ func TestLevenshtainSimilarity(t *testing.T) {
 			str2:     "gills",
 			expected: 0.6,
 		},
+		{
+			name:     "Accented single substitution",
+			str1:     "café",
+			str2:     "cafe",
+			expected: 0.75,
+		},
+		{
+			name:     "CJK single substitution",
+			str1:     "東京都",
+			str2:     "東京府",
+			expected: 0.667,
+		},
 		{
 			name:     "Cat vs. Dog",
 			str1:     "cat",
@@ -259,3 +308,42 @@ func TestLevenshtainSimilarity(t *testing.T) {
 	}
 
 }
+
+func TestScoresInUnitInterval(t *testing.T) {
+	inRange := func(v float64) bool {
+		return !math.IsNaN(v) && v >= 0 && v <= 1
+	}
+
+	scorers := map[string]func(s1, s2 string) float64{
+		"jarowinkler": jaroWinkler,
+		"levenshtein": levenshteinSimilarity,
+		"ngram": func(s1, s2 string) float64 {
+			return getOverlapCoefficient(createNgram(s1, 2), createNgram(s2, 2))
+		},
+	}
+
+	for name, scorer := range scorers {
+		t.Run(name, func(t *testing.T) {
+			property := func(s1, s2 string) bool {
+				return inRange(scorer(s1, s2))
+			}
+			if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+
+			normalized := func(s1, s2 string, fold bool) bool {
+				n := Normalizer{Form: NormNFD, FoldDiacritics: fold}
+				return inRange(scorer(n.Normalize(s1), n.Normalize(s2)))
+			}
+			if err := quick.Check(normalized, &quick.Config{MaxCount: 500}); err != nil {
+				t.Error(err)
+			}
+
+			for _, pair := range [][2]string{{"\xff\xfe", "abc"}, {"日本語", "\xe6\x97"}, {"ǅ", "ǆ"}} {
+				if v := scorer(pair[0], pair[1]); !inRange(v) {
+					t.Errorf("Score %v out of range for %q, %q", v, pair[0], pair[1])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package scanengine
+
+import (
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	NormNone = "none"
+	NormNFC  = "nfc"
+	NormNFD  = "nfd"
+)
+
+// Normalizer brings paths and patterns to a common Unicode form before
+// they are scored, so that e.g. a precomposed "é" and "e" + U+0301 compare
+// equal. With FoldDiacritics, combining marks are dropped and "cafe"
+// matches "café".
+type Normalizer struct {
+	Form           string
+	FoldDiacritics bool
+}
+
+// removeMarks drops the combining marks. Unlike a transform.Chain, it
+// holds no state and is shared by the concurrent filters.
+var removeMarks = runes.Remove(runes.In(unicode.Mn))
+
+func (n Normalizer) Normalize(s string) string {
+	if n.FoldDiacritics {
+		if folded, _, err := transform.String(removeMarks, norm.NFD.String(s)); err == nil {
+			s = norm.NFC.String(folded)
+		}
+	}
+
+	switch n.Form {
+	case NormNFC:
+		return norm.NFC.String(s)
+	case NormNFD:
+		return norm.NFD.String(s)
+	default:
+		return s
+	}
+}
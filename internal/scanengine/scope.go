@@ -0,0 +1,69 @@
+package scanengine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ScopeAuto         = "auto"
+	ScopeBasename     = "basename"
+	ScopePath         = "path"
+	ScopeRelativePath = "relative-path"
+)
+
+var Scopes = []string{ScopeAuto, ScopeBasename, ScopePath, ScopeRelativePath}
+
+// Scope selects the part of the paths the filters match: the last
+// component, the full path, or the path relative to its scan root. The auto
+// mode matches the full path when the pattern contains a slash, and the
+// last component otherwise. An empty mode matches the path as scanned.
+type Scope struct {
+	Mode string
+	// Root is the absolute directory relative paths were scanned from.
+	Root string
+	// Roots are the directories scanned instead of Root.
+	Roots []string
+}
+
+// text returns the part of path matched for pattern, and the index of its
+// first rune in path. The index is negative when Root was prepended.
+func (s Scope) text(path, pattern string) (string, int) {
+	mode := s.Mode
+	if mode == ScopeAuto {
+		mode = ScopeBasename
+		if strings.Contains(pattern, "/") {
+			mode = ScopePath
+		}
+	}
+
+	switch mode {
+	case ScopeBasename:
+		start := strings.LastIndex(filepath.ToSlash(path), "/") + 1
+		return path[start:], utf8.RuneCountInString(path[:start])
+	case ScopePath:
+		if s.Root != "" && !filepath.IsAbs(path) {
+			prefix := withSeparator(s.Root)
+			return prefix + path, -utf8.RuneCountInString(prefix)
+		}
+	case ScopeRelativePath:
+		start := 0
+		for _, root := range s.Roots {
+			prefix := withSeparator(filepath.Clean(root))
+			if len(prefix) > start && strings.HasPrefix(path, prefix) {
+				start = len(prefix)
+			}
+		}
+		return path[start:], utf8.RuneCountInString(path[:start])
+	}
+	return path, 0
+}
+
+func withSeparator(dir string) string {
+	if strings.HasSuffix(dir, string(os.PathSeparator)) {
+		return dir
+	}
+	return dir + string(os.PathSeparator)
+}
@@ -0,0 +1,65 @@
+package scanengine
+
+import "testing"
+
+func TestScope(t *testing.T) {
+	roots := []string{"/srv", "/srv/app/", "../lib"}
+
+	testCases := []struct {
+		name     string
+		scope    Scope
+		path     string
+		pattern  string
+		expected string
+		offset   int
+	}{
+		{name: "empty", scope: Scope{}, path: "src/main.go", expected: "src/main.go"},
+		{name: "basename", scope: Scope{Mode: ScopeBasename}, path: "src/café/main.go", expected: "main.go", offset: 9},
+		{name: "basename of a file at the root", scope: Scope{Mode: ScopeBasename}, path: "main.go", expected: "main.go"},
+		{name: "path", scope: Scope{Mode: ScopePath, Root: "/home/user"}, path: "src/main.go", expected: "/home/user/src/main.go", offset: -11},
+		{name: "absolute path", scope: Scope{Mode: ScopePath, Root: "/home/user"}, path: "/srv/main.go", expected: "/srv/main.go"},
+		{name: "relative path", scope: Scope{Mode: ScopeRelativePath, Root: "/home/user"}, path: "src/main.go", expected: "src/main.go"},
+		{name: "relative to the deepest root", scope: Scope{Mode: ScopeRelativePath, Roots: roots}, path: "/srv/app/main.go", expected: "main.go", offset: 9},
+		{name: "relative to a relative root", scope: Scope{Mode: ScopeRelativePath, Roots: roots}, path: "../lib/util.go", expected: "util.go", offset: 7},
+		{name: "relative to no root", scope: Scope{Mode: ScopeRelativePath, Roots: roots}, path: "/srvx/main.go", expected: "/srvx/main.go"},
+		{name: "auto without slash", scope: Scope{Mode: ScopeAuto, Root: "/home/user"}, path: "src/main.go", pattern: "main", expected: "main.go", offset: 4},
+		{name: "auto with slash", scope: Scope{Mode: ScopeAuto, Root: "/home/user"}, path: "src/main.go", pattern: "src/main", expected: "/home/user/src/main.go", offset: -11},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			text, offset := tc.scope.text(tc.path, tc.pattern)
+			if text != tc.expected || offset != tc.offset {
+				t.Errorf("Expected %q at %d, obtained %q at %d", tc.expected, tc.offset, text, offset)
+			}
+		})
+	}
+}
+
+func TestFiltersApplyScope(t *testing.T) {
+	const path = "src/jetfind/main.go"
+	basename := Scope{Mode: ScopeBasename}
+
+	testCases := []struct {
+		name     string
+		filter   ScanFilter
+		expected bool
+	}{
+		{name: "contains in the path", filter: ContainsFilter{Pattern: "jetfind"}, expected: true},
+		{name: "contains in the basename", filter: ContainsFilter{Pattern: "jetfind", Scope: basename}, expected: false},
+		{name: "ngram in the path", filter: FuzzyFilter{Pattern: "jetfind", Algo: AlgoNGram, Threashold: 0.99}, expected: true},
+		{name: "ngram in the basename", filter: FuzzyFilter{Pattern: "jetfind", Algo: AlgoNGram, Threashold: 0.99, Scope: basename}, expected: false},
+		{name: "jarowinkler in the path", filter: FuzzyFilter{Pattern: "main.go", Algo: AlgoJaroWinkler, Threashold: 0.9}, expected: false},
+		{name: "jarowinkler in the basename", filter: FuzzyFilter{Pattern: "main.go", Algo: AlgoJaroWinkler, Threashold: 0.9, Scope: basename}, expected: true},
+		{name: "levenshtein in the path", filter: FuzzyFilter{Pattern: "src/jetfind/main.g", Algo: AlgoLevenshtein, Threashold: 0.9}, expected: true},
+		{name: "levenshtein in the basename", filter: FuzzyFilter{Pattern: "src/jetfind/main.g", Algo: AlgoLevenshtein, Threashold: 0.9, Scope: basename}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, result := tc.filter.Apply(path); result != tc.expected {
+				t.Errorf("Expected %v, but obtained %v for path '%s'", tc.expected, result, path)
+			}
+		})
+	}
+}
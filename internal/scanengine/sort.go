@@ -0,0 +1,159 @@
+package scanengine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	TiebreakScore  = "score"
+	TiebreakLength = "length"
+	TiebreakDepth  = "depth"
+	TiebreakPath   = "path"
+	TiebreakMtime  = "mtime"
+	TiebreakIndex  = "index"
+)
+
+var ValidTiebreaks = []string{TiebreakScore, TiebreakLength, TiebreakDepth, TiebreakPath, TiebreakMtime, TiebreakIndex}
+
+// SortOrder is the chain of criteria used to rank filtered results. The
+// scan index is always appended as a last resort, so the order is total and
+// the same input always produces the same output.
+type SortOrder []string
+
+var DefaultSortOrder = SortOrder{TiebreakScore, TiebreakLength, TiebreakIndex}
+
+// Ranking controls how FilterEngine orders the filtered results.
+type Ranking struct {
+	Order SortOrder
+	// Boost, when set, returns a score in [0, 1] for a path, blended with
+	// the filter score as (1-BoostWeight)*score + BoostWeight*boost. It is
+	// called concurrently.
+	Boost       func(path string) float64
+	BoostWeight float64
+}
+
+func (r Ranking) apply(result ScanFilteredResult) ScanFilteredResult {
+	if r.Boost != nil && r.BoostWeight > 0 {
+		result.Score = (1-r.BoostWeight)*result.Score + r.BoostWeight*r.Boost(result.Path)
+	}
+	return result
+}
+
+// ParseSortOrder parses a comma separated tiebreak chain such as
+// "score,length,path".
+func ParseSortOrder(s string) (SortOrder, error) {
+	order := SortOrder{}
+	for _, key := range strings.Split(s, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		order = append(order, key)
+	}
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (o SortOrder) Validate() error {
+	seen := make(map[string]bool, len(o))
+	for _, key := range o {
+		valid := false
+		for _, v := range ValidTiebreaks {
+			if key == v {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid tiebreak: %s. Must be one of: %v", key, ValidTiebreaks)
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicated tiebreak: %s", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (o SortOrder) orDefault() SortOrder {
+	if len(o) == 0 {
+		return DefaultSortOrder
+	}
+	return o
+}
+
+func (o SortOrder) needsModTime() bool {
+	for _, key := range o {
+		if key == TiebreakMtime {
+			return true
+		}
+	}
+	return false
+}
+
+// rankedResult carries the data needed by the tiebreaks that is not part of
+// the public result.
+type rankedResult struct {
+	ScanFilteredResult
+	index   int
+	modTime int64
+}
+
+func newRankedResult(r ScanFilteredResult, index int, withModTime bool) rankedResult {
+	rr := rankedResult{ScanFilteredResult: r, index: index}
+	if withModTime {
+		if info, err := os.Stat(r.Path); err == nil {
+			rr.modTime = info.ModTime().UnixNano()
+		}
+	}
+	return rr
+}
+
+func depth(path string) int {
+	return strings.Count(filepath.ToSlash(path), "/")
+}
+
+// compare returns a negative value when a ranks before b, positive when b
+// ranks before a.
+func (o SortOrder) compare(a, b *rankedResult) int {
+	for _, key := range o {
+		c := 0
+		switch key {
+		case TiebreakScore:
+			c = cmpDesc(a.Score, b.Score)
+		case TiebreakLength:
+			c = len(a.Path) - len(b.Path)
+		case TiebreakDepth:
+			c = depth(a.Path) - depth(b.Path)
+		case TiebreakPath:
+			c = strings.Compare(a.Path, b.Path)
+		case TiebreakMtime:
+			c = cmpDesc(a.modTime, b.modTime)
+		case TiebreakIndex:
+			c = a.index - b.index
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return a.index - b.index
+}
+
+func (o SortOrder) less(a, b *rankedResult) bool {
+	return o.compare(a, b) < 0
+}
+
+func cmpDesc[T int64 | float64](a, b T) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package scanengine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected SortOrder
+		wantErr  bool
+	}{
+		{input: "score,length", expected: SortOrder{"score", "length"}},
+		{input: " path , index ", expected: SortOrder{"path", "index"}},
+		{input: "", expected: SortOrder{}},
+		{input: "score,unknown", wantErr: true},
+		{input: "path,path", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			order, err := ParseSortOrder(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseSortOrder(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(order, tc.expected) {
+				t.Errorf("ParseSortOrder(%q) = %v, expected %v", tc.input, order, tc.expected)
+			}
+		})
+	}
+}
+
+func resultPaths(results []ScanFilteredResult) []string {
+	paths := make([]string, len(results))
+	for i, r := range results {
+		paths[i] = r.Path
+	}
+	return paths
+}
+
+func TestFilterEngineTiebreaks(t *testing.T) {
+	pathBuffer := []ScanFilteredResult{
+		{Path: "b/c/long_name.go"},
+		{Path: "a/zz.go"},
+		{Path: "a/b/c/x.go"},
+		{Path: "a/aa.go"},
+	}
+
+	testCases := []struct {
+		order    SortOrder
+		expected []string
+	}{
+		{order: SortOrder{"index"}, expected: []string{"b/c/long_name.go", "a/zz.go", "a/b/c/x.go", "a/aa.go"}},
+		{order: SortOrder{"path"}, expected: []string{"a/aa.go", "a/b/c/x.go", "a/zz.go", "b/c/long_name.go"}},
+		{order: SortOrder{"length"}, expected: []string{"a/zz.go", "a/aa.go", "a/b/c/x.go", "b/c/long_name.go"}},
+		{order: SortOrder{"depth", "path"}, expected: []string{"a/aa.go", "a/zz.go", "b/c/long_name.go", "a/b/c/x.go"}},
+		{order: nil, expected: []string{"a/zz.go", "a/aa.go", "a/b/c/x.go", "b/c/long_name.go"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.order), func(t *testing.T) {
+			res, err := FilterEngine(context.Background(), pathBuffer, NoFilter{}, Ranking{Order: tc.order})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := resultPaths(res); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, obtained %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterEngineMtimeTiebreak(t *testing.T) {
+	root := t.TempDir()
+	older := filepath.Join(root, "older.txt")
+	newer := filepath.Join(root, "newer.txt")
+	mustWriteFile(t, older, "old")
+	mustWriteFile(t, newer, "new")
+
+	now := time.Now()
+	if err := os.Chtimes(older, now.Add(-time.Hour), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to set file times: %v", err)
+	}
+
+	pathBuffer := []ScanFilteredResult{{Path: older}, {Path: newer}}
+	res, err := FilterEngine(context.Background(), pathBuffer, NoFilter{}, Ranking{Order: SortOrder{"mtime"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res[0].Path != newer {
+		t.Errorf("Expected the most recently modified file first, obtained %v", resultPaths(res))
+	}
+}
+
+func TestFilterEngineDeterministic(t *testing.T) {
+	for _, size := range []int{60, 3000} {
+		pathBuffer := make([]ScanFilteredResult, 0, size)
+		for i := range size {
+			pathBuffer = append(pathBuffer, ScanFilteredResult{Path: fmt.Sprintf("dir%d/file%d.go", i%7, i%13)})
+		}
+		scanFilter := FuzzyFilter{Pattern: "file1.go", Algo: AlgoJaroWinkler, Threashold: 0.5}
+
+		first, err := FilterEngine(context.Background(), pathBuffer, scanFilter, Ranking{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for range 10 {
+			again, err := FilterEngine(context.Background(), pathBuffer, scanFilter, Ranking{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(first, again) {
+				t.Fatalf("Size %d: results order changed between runs", size)
+			}
+		}
+
+		top, _, err := FilterEngineTopK(context.Background(), pathBuffer, scanFilter, Ranking{}, 20)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(top, first[:20]) {
+			t.Errorf("Size %d: top-K order differs from the full ranking", size)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Package shellquote quotes strings so a shell reads them back unchanged.
+package shellquote
+
+import "strings"
+
+// Posix quotes s for sh, bash and zsh.
+func Posix(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Fish quotes s for fish, where backslashes are special inside single
+// quotes too.
+func Fish(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,78 @@
+package shellquote
+
+import (
+	"os/exec"
+	"testing"
+)
+
+var quotingCases = []string{
+	"main.go",
+	"my file.txt",
+	"it's",
+	`back\slash`,
+	"$HOME and `cmd`",
+	"new\nline",
+	"*.go",
+}
+
+func TestPosix(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "main.go", expected: "'main.go'"},
+		{input: "my file.txt", expected: "'my file.txt'"},
+		{input: "it's", expected: `'it'\''s'`},
+	}
+
+	for _, tc := range testCases {
+		if obtained := Posix(tc.input); obtained != tc.expected {
+			t.Errorf("Posix(%q): expected %s, obtained %s", tc.input, tc.expected, obtained)
+		}
+	}
+}
+
+func TestFish(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "main.go", expected: "'main.go'"},
+		{input: "it's", expected: `'it\'s'`},
+		{input: `a\b`, expected: `'a\\b'`},
+	}
+
+	for _, tc := range testCases {
+		if obtained := Fish(tc.input); obtained != tc.expected {
+			t.Errorf("Fish(%q): expected %s, obtained %s", tc.input, tc.expected, obtained)
+		}
+	}
+}
+
+// roundTrip has shell print the quoted strings back.
+func roundTrip(t *testing.T, shell string, quote func(string) string) {
+	if _, err := exec.LookPath(shell); err != nil {
+		t.Skipf("%s is not installed", shell)
+	}
+	for _, s := range quotingCases {
+		out, err := exec.Command(shell, "-c", "printf %s "+quote(s)).Output()
+		if err != nil {
+			t.Fatalf("%s failed for %q: %v", shell, s, err)
+		}
+		if string(out) != s {
+			t.Errorf("%s read %q back as %q", shell, s, out)
+		}
+	}
+}
+
+func TestPosixRoundTrip(t *testing.T) {
+	for _, shell := range []string{"sh", "bash", "zsh"} {
+		t.Run(shell, func(t *testing.T) {
+			roundTrip(t, shell, Posix)
+		})
+	}
+}
+
+func TestFishRoundTrip(t *testing.T) {
+	roundTrip(t, "fish", Fish)
+}
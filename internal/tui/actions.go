@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"jetfind/internal/keymap"
+	"jetfind/internal/scanengine"
+	"jetfind/internal/shellquote"
+	"math"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runActions runs a chain of bound actions. The chain stops at accept and
+// abort, which end the program.
+func (m *Model) runActions(actions []keymap.Action) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, a := range actions {
+		switch a.Name {
+		case keymap.Accept:
+			return tea.Batch(append(cmds, m.accept())...)
+		case keymap.Abort:
+			m.cancelFiltering()
+			m.quitting = true
+			m.aborted = true
+			return tea.Quit
+		default:
+			cmds = append(cmds, m.runAction(a))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) runAction(a keymap.Action) tea.Cmd {
+	switch a.Name {
+	case keymap.Up:
+		m.moveUp(1)
+	case keymap.Down:
+		m.moveUp(-1)
+	case keymap.PageUp:
+		m.moveUp(m.page())
+	case keymap.PageDown:
+		m.moveUp(-m.page())
+	case keymap.HalfPageUp:
+		m.moveUp(max(1, m.page()/2))
+	case keymap.HalfPageDown:
+		m.moveUp(-max(1, m.page()/2))
+	case keymap.First:
+		m.setCursor(0)
+	case keymap.Last:
+		m.setCursor(len(m.filteredPaths) - 1)
+	case keymap.ToggleMark:
+		m.toggleMark()
+	case keymap.PreviewToggle:
+		m.preview = !m.preview
+	case keymap.CopyPath:
+		if path, ok := m.highlighted(); ok {
+			return copyCmd(path)
+		}
+	case keymap.CycleFilter:
+		m.cycleFilter()
+		m.tune("filter.type", "filter.algorithm")
+		return m.queryChanged()
+	case keymap.ThresholdUp:
+		m.nudgeThreshold(thresholdStep)
+		m.tune("filter.threshold")
+		return m.queryChanged()
+	case keymap.ThresholdDown:
+		m.nudgeThreshold(-thresholdStep)
+		m.tune("filter.threshold")
+		return m.queryChanged()
+	case keymap.CycleCase:
+		m.cfg.Filter.Case = nextOf(scanengine.CaseModes, m.caseMode())
+		m.tune("filter.case")
+		return m.queryChanged()
+	case keymap.CycleScope:
+		m.cfg.Filter.Scope = nextOf(scanengine.Scopes, m.scope())
+		m.tune("filter.scope")
+		return m.queryChanged()
+	case keymap.PrevHistory:
+		if m.historyPrev() {
+			return m.queryChanged()
+		}
+	case keymap.NextHistory:
+		if m.historyNext() {
+			return m.queryChanged()
+		}
+	case keymap.HistorySearch:
+		m.startHistorySearch()
+	case keymap.ClearQuery:
+		if m.userQuery.Set("") {
+			m.historyPos = -1
+			return m.queryChanged()
+		}
+	case keymap.Help:
+		m.showHelp = !m.showHelp
+	case keymap.Execute:
+		return m.execute(a.Arg)
+	}
+	return nil
+}
+
+// queryChanged moves back to the best result and refilters.
+func (m *Model) queryChanged() tea.Cmd {
+	m.setCursor(0)
+	return m.requestFiltering()
+}
+
+func (m *Model) highlighted() (string, bool) {
+	if m.cursor >= len(m.filteredPaths) {
+		return "", false
+	}
+	return m.filteredPaths[m.cursor].Path, true
+}
+
+// thresholdStep is the change of the fuzzy threshold by threshold-up and
+// threshold-down.
+const thresholdStep = 0.05
+
+// filterMode returns the current filter type and algorithm.
+func (m *Model) filterMode() scanengine.FilterMode {
+	mode := scanengine.FilterMode{Type: m.cfg.Filter.Type}
+	if mode.Type == scanengine.TypeFuzzy {
+		mode.Algo = m.cfg.Filter.Algo
+	}
+	return mode
+}
+
+// cycleFilter switches to the next of scanengine.FilterModes. The contains
+// filter keeps the algorithm for when the fuzzy one is back.
+func (m *Model) cycleFilter() {
+	modes := scanengine.FilterModes()
+	next := modes[0]
+	for i, mode := range modes {
+		if mode == m.filterMode() {
+			next = modes[(i+1)%len(modes)]
+		}
+	}
+	m.cfg.Filter.Type = next.Type
+	if next.Algo != "" {
+		m.cfg.Filter.Algo = next.Algo
+	}
+}
+
+// nudgeThreshold changes the fuzzy threshold by delta, within [0, 1].
+func (m *Model) nudgeThreshold(delta float64) {
+	threshold := math.Round((m.cfg.Filter.Threshold+delta)*100) / 100
+	m.cfg.Filter.Threshold = min(1, max(0, threshold))
+}
+
+// tune records settings changed from the TUI, which a reload keeps.
+func (m *Model) tune(settings ...string) {
+	if m.tuned == nil {
+		m.tuned = make(map[string]bool)
+	}
+	for _, s := range settings {
+		m.tuned[s] = true
+	}
+}
+
+// nextOf returns the value following current in values, wrapping around.
+func nextOf(values []string, current string) string {
+	for i, v := range values {
+		if v == current {
+			return values[(i+1)%len(values)]
+		}
+	}
+	return values[0]
+}
+
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		}
+		_, err := seq.WriteTo(os.Stderr)
+		if err != nil {
+			return noticeMsg("Copy failed: " + err.Error())
+		}
+		return noticeMsg("Copied " + text)
+	}
+}
+
+// execute runs command in a shell while the TUI is suspended.
+func (m *Model) execute(command string) tea.Cmd {
+	path, ok := m.highlighted()
+	if !ok {
+		return nil
+	}
+	quoted := []string{shellquote.Posix(path)}
+	if len(m.marks) > 0 {
+		quoted = make([]string, len(m.marks))
+		for i, marked := range m.marks {
+			quoted[i] = shellquote.Posix(marked.Path)
+		}
+	}
+	command = strings.ReplaceAll(command, "{+}", strings.Join(quoted, " "))
+	command = strings.ReplaceAll(command, "{}", shellquote.Posix(path))
+
+	return tea.ExecProcess(exec.Command("sh", "-c", command), func(err error) tea.Msg {
+		if err != nil {
+			return noticeMsg("Command failed: " + err.Error())
+		}
+		return nil
+	})
+}
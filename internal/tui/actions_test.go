@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"jetfind/internal/config"
+	"jetfind/internal/scanengine"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMarkAndAccept(t *testing.T) {
+	m := newListModel(10, 10+headerLines)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if len(m.marks) != 2 || m.cursor != 3 {
+		t.Fatalf("Expected 2 marks and cursor 3, obtained %v and %d", m.marks, m.cursor)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if !reflect.DeepEqual(paths(m.marks), []string{"file000", "file003"}) {
+		t.Fatalf("Unexpected marks %v", m.marks)
+	}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("Expected enter to quit")
+	}
+	if !reflect.DeepEqual(paths(m.Selected), []string{"file000", "file003"}) {
+		t.Errorf("Expected the marked files to be selected, obtained %v", m.Selected)
+	}
+}
+
+func TestCustomBindings(t *testing.T) {
+	cfg := *config.Default
+	cfg.Tui.Keys = map[string]string{"ctrl-j": "last+accept", "enter": "ignore"}
+	m := NewModel(&cfg, nil, nil)
+	for _, p := range []string{"a", "b", "c"} {
+		m.filteredPaths = append(m.filteredPaths, scanengine.ScanFilteredResult{Path: p})
+	}
+	m.Update(tea.WindowSizeMsg{Height: 20})
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.Selected != nil {
+		t.Error("Expected enter to be unbound")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	if !reflect.DeepEqual(paths(m.Selected), []string{"c"}) {
+		t.Errorf("Expected [c] to be selected, obtained %v", m.Selected)
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	m := newListModel(3, 10+headerLines)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyF1})
+	if !m.showHelp || !m.userQuery.IsEmpty() {
+		t.Fatal("Expected f1 to open the help")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m.showHelp || !m.userQuery.IsEmpty() {
+		t.Error("Expected any key to close the help without editing the query")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if m.showHelp || m.userQuery.String() != "?" {
+		t.Errorf("Expected ? to be typed, obtained %q", m.userQuery.String())
+	}
+}
+
+func TestInvalidKeyBindingsReported(t *testing.T) {
+	DefaultStyles()
+	cfg := *config.Default
+	cfg.Tui.Keys = map[string]string{"ctrl-y": "launch"}
+	m := NewModel(&cfg, nil, nil)
+	if m.keysErr == nil {
+		t.Fatal("Expected the invalid binding to be reported")
+	}
+	if _, ok := m.keys["enter"]; !ok {
+		t.Error("Expected the default bindings to be kept")
+	}
+	if view := m.View(); !strings.Contains(view, "Key bindings ignored") {
+		t.Errorf("Expected the problem in the status line, obtained %q", view)
+	}
+}
+
+func TestCycleFilter(t *testing.T) {
+	m := newListModel(3, 10+headerLines)
+	m.cfg.Filter.Type = scanengine.TypeFuzzy
+	m.cfg.Filter.Algo = scanengine.AlgoJaroWinkler
+
+	for _, expected := range []string{"fuzzy/ngram", "fuzzy/levenshtein", "contains", "fuzzy/jarowinkler"} {
+		m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		if mode := m.filterMode().String(); mode != expected {
+			t.Errorf("Expected filter mode %s, obtained %s", expected, mode)
+		}
+	}
+}
+
+func TestNudgeThreshold(t *testing.T) {
+	DefaultStyles()
+	m := newListModel(3, 10+headerLines)
+	m.cfg.Filter.Type = scanengine.TypeFuzzy
+	m.cfg.Filter.Threshold = 0.9
+	m.userQuery.Set("file")
+
+	testCases := []struct {
+		key      tea.KeyMsg
+		expected float64
+	}{
+		{key: tea.KeyMsg{Type: tea.KeyDown, Alt: true}, expected: 0.85},
+		{key: tea.KeyMsg{Type: tea.KeyUp, Alt: true}, expected: 0.9},
+		{key: tea.KeyMsg{Type: tea.KeyUp, Alt: true}, expected: 0.95},
+		{key: tea.KeyMsg{Type: tea.KeyUp, Alt: true}, expected: 1},
+		{key: tea.KeyMsg{Type: tea.KeyUp, Alt: true}, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		filterGen := m.filterGen
+		m.Update(tc.key)
+		if m.cfg.Filter.Threshold != tc.expected {
+			t.Errorf("Expected threshold %v after %s, obtained %v", tc.expected, tc.key, m.cfg.Filter.Threshold)
+		}
+		if m.filterGen != filterGen+1 {
+			t.Errorf("Expected %s to filter again", tc.key)
+		}
+	}
+	if view := m.View(); !strings.Contains(view, "Search [fuzzy/jarowinkler, threshold 1.00, smart, auto]") {
+		t.Errorf("Expected the filter mode and threshold in the query box, obtained %q", view)
+	}
+}
+
+func TestCycleScope(t *testing.T) {
+	DefaultStyles()
+	m := newListModel(3, 10+headerLines)
+	m.cfg.Filter.Type = scanengine.TypeContains
+	m.userQuery.Set("file")
+
+	for _, expected := range []string{"basename", "path", "relative-path", "auto"} {
+		filterGen := m.filterGen
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}, Alt: true})
+		if m.cfg.Filter.Scope != expected {
+			t.Errorf("Expected scope %s, obtained %s", expected, m.cfg.Filter.Scope)
+		}
+		if m.filterGen != filterGen+1 {
+			t.Errorf("Expected the %s scope to filter again", expected)
+		}
+		if view := m.View(); !strings.Contains(view, "Search [contains, smart, "+expected+"]") {
+			t.Errorf("Expected the scope in the query box, obtained %q", view)
+		}
+	}
+}
+
+func TestAutoSelect(t *testing.T) {
+	testCases := []struct {
+		name             string
+		selectOne        bool
+		exitZero         bool
+		matches          []string
+		expectedQuit     bool
+		expectedSelected []string
+	}{
+		{name: "select one match", selectOne: true, matches: []string{"a"}, expectedQuit: true, expectedSelected: []string{"a"}},
+		{name: "select one of two matches", selectOne: true, matches: []string{"a", "b"}},
+		{name: "select one without matches", selectOne: true},
+		{name: "exit without matches", exitZero: true, expectedQuit: true},
+		{name: "exit with matches", exitZero: true, matches: []string{"a"}},
+		{name: "disabled", matches: []string{"a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := *config.Default
+			m := NewModel(&cfg, nil, nil)
+			m.selectOne = tc.selectOne
+			m.exitZero = tc.exitZero
+			for _, p := range tc.matches {
+				m.Update(newPathMsg(scanengine.ScanFilteredResult{Path: p}))
+			}
+			m.Update(scanDoneMsg{})
+
+			if m.quitting != tc.expectedQuit {
+				t.Errorf("Expected quitting %v, obtained %v", tc.expectedQuit, m.quitting)
+			}
+			if !reflect.DeepEqual(paths(m.Selected), tc.expectedSelected) {
+				t.Errorf("Expected selection %v, obtained %v", tc.expectedSelected, paths(m.Selected))
+			}
+		})
+	}
+}
+
+func TestAcceptAndAbortWithoutSelection(t *testing.T) {
+	m := newListModel(0, 10+headerLines)
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || !m.quitting {
+		t.Fatal("Expected enter to quit without results")
+	}
+	if m.Selected != nil || m.aborted {
+		t.Errorf("Expected an empty selection, obtained %v (aborted %v)", m.Selected, m.aborted)
+	}
+
+	m = newListModel(3, 10+headerLines)
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.aborted || m.Selected != nil {
+		t.Errorf("Expected esc to abort without a selection, obtained %v (aborted %v)", m.Selected, m.aborted)
+	}
+}
+
+func TestQueryFiltersStreamedPaths(t *testing.T) {
+	cfg := *config.Default
+	m := NewModel(&cfg, nil, nil)
+	m.userQuery.Set("b")
+
+	m.Update(newPathMsg(scanengine.ScanFilteredResult{Path: "a"}))
+	if !m.filtering || m.filterGen != 1 {
+		t.Fatalf("Expected the first path to start filtering, obtained generation %d", m.filterGen)
+	}
+	m.Update(newPathMsg(scanengine.ScanFilteredResult{Path: "b"}))
+	if m.filterGen != 1 {
+		t.Errorf("Expected a single filter job in flight, obtained generation %d", m.filterGen)
+	}
+
+	m.Update(filterDoneMsg{gen: 1})
+	if !m.filtering || m.filterGen != 2 || m.filteredScanned != 2 {
+		t.Errorf("Expected the paths scanned meanwhile to be filtered, obtained generation %d over %d paths", m.filterGen, m.filteredScanned)
+	}
+
+	m.Update(scanDoneMsg{})
+	m.Update(filterDoneMsg{gen: m.filterGen, results: m.scannedPaths[1:], total: 1})
+	if m.filtering || !reflect.DeepEqual(paths(m.filteredPaths), []string{"b"}) {
+		t.Errorf("Expected filtering to end with [b], obtained %v", paths(m.filteredPaths))
+	}
+}
+
+func TestStatusShowsTruncatedMatches(t *testing.T) {
+	DefaultStyles()
+	testCases := []struct {
+		name     string
+		total    int
+		expected string
+	}{
+		{name: "all shown", total: 3, expected: "Filtered (3)"},
+		{name: "truncated", total: 52341, expected: "Filtered (3/52341 shown)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newListModel(0, 10)
+			m.userQuery.Set("file")
+			m.Update(scanDoneMsg{})
+			results := []scanengine.ScanFilteredResult{{Path: "a"}, {Path: "b"}, {Path: "c"}}
+			m.Update(filterDoneMsg{gen: m.filterGen, results: results, total: tc.total})
+			if view := m.View(); !strings.Contains(view, tc.expected) {
+				t.Errorf("Expected the status to contain %q, obtained:\n%s", tc.expected, view)
+			}
+		})
+	}
+}
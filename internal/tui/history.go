@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyPrev replaces the query with the previous query submitted in the
+// same root. The query typed before browsing is kept and restored by
+// historyNext.
+func (m *Model) historyPrev() bool {
+	if m.history == nil {
+		return false
+	}
+	if m.historyPos < 0 {
+		m.historyQueries = m.history.Queries(m.root)
+		m.savedQuery = m.userQuery.String()
+	}
+	if m.historyPos+1 >= len(m.historyQueries) {
+		return false
+	}
+	m.historyPos++
+	return m.userQuery.Set(m.historyQueries[m.historyPos])
+}
+
+func (m *Model) historyNext() bool {
+	if m.historyPos < 0 {
+		return false
+	}
+	m.historyPos--
+	if m.historyPos < 0 {
+		return m.userQuery.Set(m.savedQuery)
+	}
+	return m.userQuery.Set(m.historyQueries[m.historyPos])
+}
+
+func (m *Model) startHistorySearch() {
+	if m.history == nil {
+		return
+	}
+	m.searching = true
+	m.searchTerm = ""
+	m.searchMatch = -1
+}
+
+func (m *Model) searchMatchQuery() string {
+	if m.searchMatch < 0 {
+		return ""
+	}
+	return m.history.Entries()[m.searchMatch].Query
+}
+
+// updateHistorySearch handles keys while the reverse history search is
+// active. Accepting a match replaces the query and refilters.
+func (m *Model) updateHistorySearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+g", "ctrl+c":
+		m.searching = false
+		return nil
+	case "enter", "tab", "right":
+		m.searching = false
+		if m.searchMatch >= 0 && m.userQuery.Set(m.searchMatchQuery()) {
+			m.historyPos = -1
+			return m.queryChanged()
+		}
+		return nil
+	case "ctrl+r":
+		if m.searchTerm == "" {
+			return nil
+		}
+		if i := m.history.Search(m.searchTerm, m.searchMatch); i >= 0 {
+			m.searchMatch = i
+		}
+		return nil
+	case "backspace", "ctrl+h":
+		if m.searchTerm == "" {
+			return nil
+		}
+		_, size := utf8.DecodeLastRuneInString(m.searchTerm)
+		m.searchTerm = m.searchTerm[:len(m.searchTerm)-size]
+	default:
+		if (msg.Type != tea.KeyRunes && msg.Type != tea.KeySpace) || msg.Alt {
+			return nil
+		}
+		m.searchTerm += string(msg.Runes)
+	}
+
+	m.searchMatch = -1
+	if m.searchTerm != "" {
+		m.searchMatch = m.history.Search(m.searchTerm, len(m.history.Entries()))
+	}
+	return nil
+}
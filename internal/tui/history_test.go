@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"jetfind/internal/config"
+	"jetfind/internal/history"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newHistoryModel returns a model whose history holds queries, oldest
+// first, submitted in its root, and "other" submitted elsewhere.
+func newHistoryModel(t *testing.T, queries ...string) *Model {
+	t.Helper()
+	hist, err := history.Load(filepath.Join(t.TempDir(), "history"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := *config.Default
+	m := NewModel(&cfg, hist, nil)
+	if err := hist.Add("other", "/elsewhere"); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range queries {
+		if err := hist.Add(q, m.root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return m
+}
+
+func TestHistoryRecall(t *testing.T) {
+	m := newHistoryModel(t, "main", "readme", "test")
+	m.userQuery.Set("typed")
+
+	up := tea.KeyMsg{Type: tea.KeyCtrlP}
+	down := tea.KeyMsg{Type: tea.KeyCtrlN}
+	testCases := []struct {
+		key      tea.KeyMsg
+		expected string
+	}{
+		{key: up, expected: "test"},
+		{key: up, expected: "readme"},
+		{key: up, expected: "main"},
+		// The oldest query of the root stays; other roots are skipped.
+		{key: up, expected: "main"},
+		{key: down, expected: "readme"},
+		{key: down, expected: "test"},
+		// Stepping past the newest query restores the typed one.
+		{key: down, expected: "typed"},
+		{key: down, expected: "typed"},
+		{key: up, expected: "test"},
+	}
+
+	for i, tc := range testCases {
+		m.Update(tc.key)
+		if query := m.userQuery.String(); query != tc.expected {
+			t.Errorf("Step %d: expected %q after %s, obtained %q", i, tc.expected, tc.key, query)
+		}
+	}
+
+	// Editing the recalled query ends the browsing.
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m.historyPos != -1 {
+		t.Errorf("Expected editing to end the browsing, obtained position %d", m.historyPos)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	ctrlR := tea.KeyMsg{Type: tea.KeyCtrlR}
+	typeText := func(m *Model, text string) {
+		for _, r := range text {
+			m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	t.Run("accept", func(t *testing.T) {
+		m := newHistoryModel(t, "src/main.go", "README", "cmd/main.go")
+		m.userQuery.Set("typed")
+		m.Update(ctrlR)
+		typeText(m, "MAIN")
+		if match := m.searchMatchQuery(); match != "cmd/main.go" {
+			t.Fatalf("Expected the most recent match, obtained %q", match)
+		}
+
+		// ctrl+r steps to older matches, and stays on the oldest.
+		m.Update(ctrlR)
+		if match := m.searchMatchQuery(); match != "src/main.go" {
+			t.Errorf("Expected the older match, obtained %q", match)
+		}
+		m.Update(ctrlR)
+		if match := m.searchMatchQuery(); match != "src/main.go" {
+			t.Errorf("Expected to stay on the oldest match, obtained %q", match)
+		}
+		if m.userQuery.String() != "typed" {
+			t.Errorf("Expected the query to be kept while searching, obtained %q", m.userQuery.String())
+		}
+
+		filterGen := m.filterGen
+		m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if m.searching || m.userQuery.String() != "src/main.go" || m.filterGen != filterGen+1 {
+			t.Errorf("Expected the match to replace the query and filter again, obtained %q", m.userQuery.String())
+		}
+	})
+
+	t.Run("edit the term", func(t *testing.T) {
+		m := newHistoryModel(t, "main", "readme")
+		m.Update(ctrlR)
+		typeText(m, "mx")
+		if m.searchMatch != -1 {
+			t.Errorf("Expected no match, obtained %q", m.searchMatchQuery())
+		}
+		m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+		if match := m.searchMatchQuery(); m.searchTerm != "m" || match != "readme" {
+			t.Errorf("Expected readme for m, obtained %q for %q", match, m.searchTerm)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		m := newHistoryModel(t, "main")
+		m.userQuery.Set("typed")
+		m.Update(ctrlR)
+		typeText(m, "main")
+		m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if m.searching || m.userQuery.String() != "typed" {
+			t.Errorf("Expected the typed query back, obtained %q", m.userQuery.String())
+		}
+	})
+
+	t.Run("without history", func(t *testing.T) {
+		cfg := *config.Default
+		m := NewModel(&cfg, nil, nil)
+		m.Update(ctrlR)
+		m.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+		if m.searching || !m.userQuery.IsEmpty() {
+			t.Error("Expected no search nor recall without a history")
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxUndoSteps = 100
+
+type inputState struct {
+	value  []rune
+	cursor int
+}
+
+// queryInput is the editable query line. The cursor is an index in value,
+// between 0 and len(value).
+type queryInput struct {
+	inputState
+	undo         []inputState
+	lastInserted bool
+}
+
+func newQueryInput(value string) queryInput {
+	v := []rune(value)
+	return queryInput{inputState: inputState{value: v, cursor: len(v)}}
+}
+
+func (q *queryInput) String() string {
+	return string(q.value)
+}
+
+func (q *queryInput) IsEmpty() bool {
+	return len(q.value) == 0
+}
+
+func (q *queryInput) snapshot() {
+	state := inputState{value: append([]rune(nil), q.value...), cursor: q.cursor}
+	q.undo = append(q.undo, state)
+	if len(q.undo) > maxUndoSteps {
+		q.undo = q.undo[1:]
+	}
+}
+
+// edit records an undo step and applies fn. Consecutive insertions are
+// grouped, so undo removes a whole typed word rather than one rune.
+func (q *queryInput) edit(insertion bool, fn func()) bool {
+	before := q.String()
+	pushed := !insertion || !q.lastInserted
+	if pushed {
+		q.snapshot()
+	}
+	fn()
+	q.lastInserted = insertion
+	if q.String() == before {
+		if pushed {
+			q.undo = q.undo[:len(q.undo)-1]
+		}
+		return false
+	}
+	return true
+}
+
+func (q *queryInput) Insert(text string) bool {
+	// Pasted text can contain line breaks, which have no meaning in a query.
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, text)
+	if text == "" {
+		return false
+	}
+
+	return q.edit(true, func() {
+		r := []rune(text)
+		value := make([]rune, 0, len(q.value)+len(r))
+		value = append(value, q.value[:q.cursor]...)
+		value = append(value, r...)
+		value = append(value, q.value[q.cursor:]...)
+		q.value = value
+		q.cursor += len(r)
+	})
+}
+
+// Set replaces the whole value, as an undoable edit, and moves the cursor
+// to the end.
+func (q *queryInput) Set(value string) bool {
+	return q.edit(false, func() {
+		q.value = []rune(value)
+		q.cursor = len(q.value)
+	})
+}
+
+func (q *queryInput) deleteRange(start, end int) bool {
+	if start >= end {
+		return false
+	}
+	return q.edit(false, func() {
+		q.value = append(q.value[:start:start], q.value[end:]...)
+		q.cursor = start
+	})
+}
+
+func (q *queryInput) Backspace() bool {
+	return q.deleteRange(max(0, q.cursor-1), q.cursor)
+}
+
+func (q *queryInput) Delete() bool {
+	return q.deleteRange(q.cursor, min(len(q.value), q.cursor+1))
+}
+
+func (q *queryInput) DeleteWordBackward() bool {
+	return q.deleteRange(q.wordStart(), q.cursor)
+}
+
+func (q *queryInput) KillToStart() bool {
+	return q.deleteRange(0, q.cursor)
+}
+
+func (q *queryInput) KillToEnd() bool {
+	return q.deleteRange(q.cursor, len(q.value))
+}
+
+func (q *queryInput) Undo() bool {
+	if len(q.undo) == 0 {
+		return false
+	}
+	q.inputState = q.undo[len(q.undo)-1]
+	q.undo = q.undo[:len(q.undo)-1]
+	q.lastInserted = false
+	return true
+}
+
+func (q *queryInput) move(cursor int) {
+	q.cursor = max(0, min(len(q.value), cursor))
+	q.lastInserted = false
+}
+
+func (q *queryInput) Left()      { q.move(q.cursor - 1) }
+func (q *queryInput) Right()     { q.move(q.cursor + 1) }
+func (q *queryInput) Home()      { q.move(0) }
+func (q *queryInput) End()       { q.move(len(q.value)) }
+func (q *queryInput) WordLeft()  { q.move(q.wordStart()) }
+func (q *queryInput) WordRight() { q.move(q.wordEnd()) }
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (q *queryInput) wordStart() int {
+	i := q.cursor
+	for i > 0 && !isWordRune(q.value[i-1]) {
+		i--
+	}
+	for i > 0 && isWordRune(q.value[i-1]) {
+		i--
+	}
+	return i
+}
+
+func (q *queryInput) wordEnd() int {
+	i := q.cursor
+	for i < len(q.value) && !isWordRune(q.value[i]) {
+		i++
+	}
+	for i < len(q.value) && isWordRune(q.value[i]) {
+		i++
+	}
+	return i
+}
+
+// HandleKey applies an editing key to the input. It reports whether the key
+// was an editing key and whether the text changed.
+func (q *queryInput) HandleKey(key string, runes []rune) (handled bool, changed bool) {
+	switch key {
+	case "left", "ctrl+b":
+		q.Left()
+	case "right", "ctrl+f":
+		q.Right()
+	case "home", "ctrl+a":
+		q.Home()
+	case "end", "ctrl+e":
+		q.End()
+	case "alt+left", "ctrl+left", "alt+b":
+		q.WordLeft()
+	case "alt+right", "ctrl+right", "alt+f":
+		q.WordRight()
+	case "backspace", "ctrl+h":
+		return true, q.Backspace()
+	case "delete":
+		return true, q.Delete()
+	case "ctrl+w", "alt+backspace":
+		return true, q.DeleteWordBackward()
+	case "ctrl+u":
+		return true, q.KillToStart()
+	case "ctrl+k":
+		return true, q.KillToEnd()
+	case "ctrl+z", "ctrl+_":
+		return true, q.Undo()
+	default:
+		if runes != nil {
+			return true, q.Insert(string(runes))
+		}
+		return false, false
+	}
+	return true, false
+}
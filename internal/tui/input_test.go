@@ -0,0 +1,102 @@
+package tui
+
+import "testing"
+
+func TestQueryInputEditing(t *testing.T) {
+	testCases := []struct {
+		name           string
+		initial        string
+		keys           []string
+		expected       string
+		expectedCursor int
+	}{
+		{name: "insert at end", initial: "mai", keys: []string{"n"}, expected: "main", expectedCursor: 4},
+		{name: "insert in the middle", initial: "man", keys: []string{"left", "i"}, expected: "main", expectedCursor: 3},
+		{name: "home and end", initial: "ain", keys: []string{"home", "m", "end", "."}, expected: "main.", expectedCursor: 5},
+		{name: "backspace multibyte", initial: "café", keys: []string{"backspace"}, expected: "caf", expectedCursor: 3},
+		{name: "backspace at start", initial: "main", keys: []string{"home", "backspace"}, expected: "main", expectedCursor: 0},
+		{name: "delete forward", initial: "mxain", keys: []string{"home", "right", "delete"}, expected: "main", expectedCursor: 1},
+		{name: "delete at end", initial: "main", keys: []string{"delete"}, expected: "main", expectedCursor: 4},
+		{name: "word left", initial: "src/main.go", keys: []string{"alt+b", "alt+b", "x"}, expected: "src/xmain.go", expectedCursor: 5},
+		{name: "word right", initial: "src/main.go", keys: []string{"home", "alt+f", "x"}, expected: "srcx/main.go", expectedCursor: 4},
+		{name: "delete word backward", initial: "src/main.go", keys: []string{"ctrl+w"}, expected: "src/main.", expectedCursor: 9},
+		{name: "kill to start", initial: "src/main.go", keys: []string{"alt+b", "ctrl+u"}, expected: "go", expectedCursor: 0},
+		{name: "kill to end", initial: "src/main.go", keys: []string{"home", "alt+f", "ctrl+k"}, expected: "src", expectedCursor: 3},
+		{name: "cursor stays in bounds", initial: "ab", keys: []string{"right", "right", "left", "left", "left"}, expected: "ab", expectedCursor: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newQueryInput(tc.initial)
+			for _, key := range tc.keys {
+				var runes []rune
+				if len([]rune(key)) == 1 {
+					runes = []rune(key)
+				}
+				q.HandleKey(key, runes)
+			}
+			if q.String() != tc.expected {
+				t.Errorf("Expected value %q, got %q", tc.expected, q.String())
+			}
+			if q.cursor != tc.expectedCursor {
+				t.Errorf("Expected cursor %d, got %d", tc.expectedCursor, q.cursor)
+			}
+		})
+	}
+}
+
+func TestQueryInputPaste(t *testing.T) {
+	q := newQueryInput("ab")
+	q.Left()
+	if !q.Insert("x\ny") {
+		t.Fatal("Insert() reported no change")
+	}
+	if q.String() != "ax yb" {
+		t.Errorf("Expected %q, got %q", "ax yb", q.String())
+	}
+}
+
+func TestQueryInputUndo(t *testing.T) {
+	q := newQueryInput("")
+	for _, r := range "main" {
+		q.Insert(string(r))
+	}
+	q.Left()
+	q.Backspace()
+	q.End()
+	q.Insert(".go")
+
+	expected := []string{"man.go", "man", "main", ""}
+	for _, exp := range expected {
+		if q.String() != exp {
+			t.Fatalf("Expected %q, got %q", exp, q.String())
+		}
+		q.Undo()
+	}
+
+	if q.Undo() {
+		t.Error("Undo() with an empty history reported a change")
+	}
+}
+
+func TestQueryInputNoChange(t *testing.T) {
+	q := newQueryInput("")
+	if _, changed := q.HandleKey("backspace", nil); changed {
+		t.Error("Backspace on an empty input reported a change")
+	}
+	if handled, _ := q.HandleKey("up", nil); handled {
+		t.Error("Non editing key reported as handled")
+	}
+	if len(q.undo) != 0 {
+		t.Errorf("No-op edits must not be recorded, got %d undo steps", len(q.undo))
+	}
+}
+
+func TestInitialQueryIsNotUndone(t *testing.T) {
+	q := newQueryInput("main")
+	q.Insert(".go")
+	q.Undo()
+	if q.Undo() || q.String() != "main" {
+		t.Errorf("Expected the initial query to stay, obtained %q", q.String())
+	}
+}
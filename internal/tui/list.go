@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"jetfind/internal/config"
+	"jetfind/internal/scanengine"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Lines used by the query box and the separator above the list, and by the
+// status line below it.
+const (
+	headerLines = 4
+	footerLines = 1
+)
+
+const (
+	wheelStep           = 3
+	doubleClickInterval = 400 * time.Millisecond
+)
+
+// clampCursor keeps cursor inside a list of total rows.
+func clampCursor(cursor, total int) int {
+	return max(0, min(cursor, total-1))
+}
+
+// scrollOffset returns the first visible row so that cursor stays inside a
+// window of visible rows, moving the window as little as possible. It also
+// keeps the window filled when the list or the terminal shrinks.
+func scrollOffset(cursor, offset, visible, total int) int {
+	if visible <= 0 {
+		return cursor
+	}
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+visible {
+		offset = cursor - visible + 1
+	}
+	offset = min(offset, total-visible)
+	return max(0, offset)
+}
+
+// Smallest inline TUI: the query box, one result and the status line.
+const minInlineHeight = headerLines + 1 + footerLines
+
+func (m *Model) visibleRows() int {
+	return max(0, m.height-headerLines)
+}
+
+// inline reports whether the TUI is drawn below the prompt rather than on
+// the alternate screen.
+func (m *Model) inline() bool {
+	return m.cfg.Tui.Height != ""
+}
+
+func (m *Model) reverse() bool {
+	return m.cfg.Tui.Layout == config.LayoutReverse
+}
+
+// viewHeight returns the number of lines drawn in a terminal of termHeight
+// lines.
+func (m *Model) viewHeight(termHeight int) int {
+	value, percent, err := config.ParseHeight(m.cfg.Tui.Height)
+	if err != nil {
+		return termHeight
+	}
+	if percent {
+		value = termHeight * value / 100
+	}
+	return min(termHeight, max(value, minInlineHeight))
+}
+
+func (m *Model) moveCursor(delta int) {
+	m.setCursor(m.cursor + delta)
+}
+
+// moveUp moves the highlight up on screen, which is towards the worse
+// results unless the layout is reversed.
+func (m *Model) moveUp(rows int) {
+	if m.reverse() {
+		rows = -rows
+	}
+	m.moveCursor(-rows)
+}
+
+func (m *Model) setCursor(cursor int) {
+	m.cursor = clampCursor(cursor, len(m.filteredPaths))
+	m.offset = scrollOffset(m.cursor, m.offset, m.visibleRows(), len(m.filteredPaths))
+}
+
+// page is the number of rows moved by page-up and page-down.
+func (m *Model) page() int {
+	return max(1, m.visibleRows())
+}
+
+func (m *Model) toggleMark() {
+	if m.cursor >= len(m.filteredPaths) {
+		return
+	}
+	result := m.filteredPaths[m.cursor]
+	for i, marked := range m.marks {
+		if marked.Path == result.Path {
+			m.marks = append(m.marks[:i], m.marks[i+1:]...)
+			return
+		}
+	}
+	m.marks = append(m.marks, result)
+}
+
+func (m *Model) isMarked(path string) bool {
+	for _, marked := range m.marks {
+		if marked.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// rowAt returns the index of the result rendered at screen line y, or -1.
+func (m *Model) rowAt(y int) int {
+	row := y - headerLines
+	if m.reverse() {
+		row = m.visibleRows() - 1 - (y - footerLines)
+	}
+	if row < 0 || row >= m.visibleRows() {
+		return -1
+	}
+	index := m.offset + row
+	if index >= len(m.filteredPaths) {
+		return -1
+	}
+	return index
+}
+
+// updateMouse scrolls with the wheel, highlights the clicked row and
+// accepts it on double click.
+func (m *Model) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.moveUp(wheelStep)
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.moveUp(-wheelStep)
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		index := m.rowAt(msg.Y)
+		if index < 0 {
+			return nil
+		}
+		now := time.Now()
+		doubleClick := index == m.lastClickRow && now.Sub(m.lastClickTime) <= doubleClickInterval
+		m.lastClickRow = index
+		m.lastClickTime = now
+		m.setCursor(index)
+		if doubleClick {
+			return m.accept()
+		}
+	}
+	return nil
+}
+
+// accept selects the marked results, or the highlighted one when nothing
+// is marked, and quits. Without results it quits with an empty selection.
+func (m *Model) accept() tea.Cmd {
+	switch {
+	case len(m.marks) > 0:
+		m.Selected = append([]scanengine.ScanFilteredResult(nil), m.marks...)
+	case m.cursor < len(m.filteredPaths):
+		m.Selected = []scanengine.ScanFilteredResult{m.filteredPaths[m.cursor]}
+	}
+	m.cancelFiltering()
+	m.recordQuery()
+	m.quitting = true
+	return tea.Quit
+}
@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"jetfind/internal/config"
+	"jetfind/internal/scanengine"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestScrollOffset(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cursor   int
+		offset   int
+		visible  int
+		total    int
+		expected int
+	}{
+		{name: "cursor inside window", cursor: 5, offset: 3, visible: 10, total: 100, expected: 3},
+		{name: "cursor above window", cursor: 2, offset: 3, visible: 10, total: 100, expected: 2},
+		{name: "cursor below window", cursor: 20, offset: 3, visible: 10, total: 100, expected: 11},
+		{name: "jump to last", cursor: 99, offset: 0, visible: 10, total: 100, expected: 90},
+		{name: "fewer rows than window", cursor: 3, offset: 0, visible: 10, total: 5, expected: 0},
+		{name: "terminal grows", cursor: 95, offset: 90, visible: 20, total: 100, expected: 80},
+		{name: "terminal shrinks", cursor: 15, offset: 10, visible: 3, total: 100, expected: 13},
+		{name: "list shrinks", cursor: 4, offset: 40, visible: 10, total: 5, expected: 0},
+		{name: "no visible rows", cursor: 7, offset: 0, visible: 0, total: 100, expected: 7},
+		{name: "empty list", cursor: 0, offset: 0, visible: 10, total: 0, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obtained := scrollOffset(tc.cursor, tc.offset, tc.visible, tc.total)
+			if obtained != tc.expected {
+				t.Errorf("Expected offset %d, obtained %d", tc.expected, obtained)
+			}
+		})
+	}
+}
+
+func TestClampCursor(t *testing.T) {
+	testCases := []struct {
+		cursor   int
+		total    int
+		expected int
+	}{
+		{cursor: -3, total: 10, expected: 0},
+		{cursor: 4, total: 10, expected: 4},
+		{cursor: 12, total: 10, expected: 9},
+		{cursor: 2, total: 0, expected: 0},
+	}
+
+	for _, tc := range testCases {
+		if obtained := clampCursor(tc.cursor, tc.total); obtained != tc.expected {
+			t.Errorf("clampCursor(%d, %d): expected %d, obtained %d", tc.cursor, tc.total, tc.expected, obtained)
+		}
+	}
+}
+
+// newListModel returns a model showing n results in a terminal of the given
+// height.
+func newListModel(n, height int) *Model {
+	cfg := *config.Default
+	m := NewModel(&cfg, nil, nil)
+	for i := range n {
+		m.filteredPaths = append(m.filteredPaths, scanengine.ScanFilteredResult{Path: fmt.Sprintf("file%03d", i)})
+	}
+	m.Update(tea.WindowSizeMsg{Height: height + footerLines})
+	return m
+}
+
+func paths(results []scanengine.ScanFilteredResult) []string {
+	var p []string
+	for _, r := range results {
+		p = append(p, r.Path)
+	}
+	return p
+}
+
+func TestNavigationKeys(t *testing.T) {
+	// 10 visible rows out of 100 results.
+	testCases := []struct {
+		name           string
+		keys           []tea.KeyMsg
+		expectedCursor int
+		expectedOffset int
+	}{
+		{name: "down", keys: []tea.KeyMsg{{Type: tea.KeyDown}}, expectedCursor: 1, expectedOffset: 0},
+		{name: "up at top", keys: []tea.KeyMsg{{Type: tea.KeyUp}}, expectedCursor: 0, expectedOffset: 0},
+		{name: "page down", keys: []tea.KeyMsg{{Type: tea.KeyPgDown}}, expectedCursor: 10, expectedOffset: 1},
+		{name: "page down and up", keys: []tea.KeyMsg{{Type: tea.KeyPgDown}, {Type: tea.KeyPgDown}, {Type: tea.KeyPgUp}}, expectedCursor: 10, expectedOffset: 10},
+		{name: "half page down", keys: []tea.KeyMsg{{Type: tea.KeyCtrlD}}, expectedCursor: 5, expectedOffset: 0},
+		{name: "half page up", keys: []tea.KeyMsg{{Type: tea.KeyCtrlEnd}, {Type: tea.KeyCtrlPgUp}}, expectedCursor: 94, expectedOffset: 90},
+		{name: "end", keys: []tea.KeyMsg{{Type: tea.KeyCtrlEnd}}, expectedCursor: 99, expectedOffset: 90},
+		{name: "end then home", keys: []tea.KeyMsg{{Type: tea.KeyCtrlEnd}, {Type: tea.KeyCtrlHome}}, expectedCursor: 0, expectedOffset: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newListModel(100, 10+headerLines)
+			for _, key := range tc.keys {
+				m.Update(key)
+			}
+			if m.cursor != tc.expectedCursor {
+				t.Errorf("Expected cursor %d, obtained %d", tc.expectedCursor, m.cursor)
+			}
+			if m.offset != tc.expectedOffset {
+				t.Errorf("Expected offset %d, obtained %d", tc.expectedOffset, m.offset)
+			}
+		})
+	}
+}
+
+func TestNavigationLeavesEditingKeys(t *testing.T) {
+	m := newListModel(100, 10+headerLines)
+	m.userQuery.Set("src/main.go")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyHome})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if query := m.userQuery.String(); query != "o" {
+		t.Errorf("Expected home, end and ctrl+u to edit the query, obtained %q", query)
+	}
+}
+
+func TestResizeKeepsCursorVisible(t *testing.T) {
+	m := newListModel(100, 20+headerLines)
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlEnd})
+
+	m.Update(tea.WindowSizeMsg{Height: 5 + headerLines + footerLines})
+	if m.cursor < m.offset || m.cursor >= m.offset+m.visibleRows() {
+		t.Errorf("Cursor %d outside of window [%d, %d)", m.cursor, m.offset, m.offset+m.visibleRows())
+	}
+
+	m.Update(tea.WindowSizeMsg{Height: 200})
+	if m.offset != 0 {
+		t.Errorf("Expected offset 0 once every row fits, obtained %d", m.offset)
+	}
+}
+
+func TestMouse(t *testing.T) {
+	m := newListModel(100, 10+headerLines)
+
+	m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	if m.cursor != wheelStep {
+		t.Errorf("Expected cursor %d after wheel down, obtained %d", wheelStep, m.cursor)
+	}
+
+	click := tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Y: headerLines + 7}
+	if _, cmd := m.Update(click); cmd != nil {
+		t.Error("Expected a single click not to select")
+	}
+	if m.cursor != 7 {
+		t.Errorf("Expected cursor 7 after click, obtained %d", m.cursor)
+	}
+
+	if _, cmd := m.Update(click); cmd == nil {
+		t.Error("Expected a double click to select")
+	}
+	if !reflect.DeepEqual(paths(m.Selected), []string{"file007"}) {
+		t.Errorf("Expected selection [file007], obtained %v", m.Selected)
+	}
+}
+
+func TestClickOutsideList(t *testing.T) {
+	m := newListModel(3, 10+headerLines)
+	for _, y := range []int{0, headerLines + 3, headerLines + 50} {
+		m.Update(tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Y: y})
+		if m.cursor != 0 {
+			t.Errorf("Click at line %d moved the cursor to %d", y, m.cursor)
+		}
+	}
+}
+
+func TestViewHeight(t *testing.T) {
+	testCases := []struct {
+		height   string
+		expected int
+	}{
+		{height: "", expected: 40},
+		{height: "10", expected: 10},
+		{height: "50%", expected: 20},
+		{height: "2", expected: minInlineHeight},
+		{height: "100", expected: 40},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.height, func(t *testing.T) {
+			cfg := *config.Default
+			cfg.Tui.Height = tc.height
+			m := NewModel(&cfg, nil, nil)
+			if obtained := m.viewHeight(40); obtained != tc.expected {
+				t.Errorf("Expected %d lines, obtained %d", tc.expected, obtained)
+			}
+		})
+	}
+}
+
+func TestInlineViewKeepsItsHeight(t *testing.T) {
+	DefaultStyles()
+	for _, layout := range []string{config.LayoutDefault, config.LayoutReverse} {
+		t.Run(layout, func(t *testing.T) {
+			cfg := *config.Default
+			cfg.Tui.Height = "12"
+			cfg.Tui.Layout = layout
+			m := NewModel(&cfg, nil, nil)
+			m.filteredPaths = []scanengine.ScanFilteredResult{{Path: "a"}, {Path: "b"}}
+			m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+
+			if lines := strings.Count(m.View(), "\n") + 1; lines != 12 {
+				t.Errorf("Expected 12 lines, obtained %d", lines)
+			}
+		})
+	}
+}
+
+func TestReverseLayout(t *testing.T) {
+	DefaultStyles()
+	cfg := *config.Default
+	cfg.Tui.Layout = config.LayoutReverse
+	m := NewModel(&cfg, nil, nil)
+	for i := range 20 {
+		m.filteredPaths = append(m.filteredPaths, scanengine.ScanFilteredResult{Path: fmt.Sprintf("file%03d", i)})
+	}
+	m.Update(tea.WindowSizeMsg{Height: 10 + headerLines + footerLines})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if m.cursor != 1 {
+		t.Errorf("Expected up to move to the next result, obtained cursor %d", m.cursor)
+	}
+
+	// The best result is drawn right above the separator.
+	click := tea.MouseMsg{Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Y: footerLines + 9}
+	m.Update(click)
+	if m.cursor != 0 {
+		t.Errorf("Expected the bottom row to be the first result, obtained cursor %d", m.cursor)
+	}
+
+	view := m.View()
+	if !strings.HasSuffix(strings.TrimRight(view, "\n"), "╯") {
+		t.Error("Expected the query box at the bottom of the view")
+	}
+}
+
+func TestRenderRow(t *testing.T) {
+	Styles = NewTheme(config.Themes[config.ThemeNoColor])
+	m := NewModel(config.Default, nil, nil)
+	m.marks = []scanengine.ScanFilteredResult{{Path: "src/main.go"}}
+
+	testCases := []struct {
+		name     string
+		path     string
+		current  bool
+		expected string
+	}{
+		{name: "plain", path: "src/util.go", expected: "   0.5  src/util.go"},
+		{name: "marked", path: "src/main.go", expected: " + 0.5  src/main.go"},
+		{name: "current", path: "src/util.go", current: true, expected: "❯  0.5  src/util.go"},
+		{name: "current and marked", path: "src/main.go", current: true, expected: "❯+ 0.5  src/main.go"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := scanengine.ScanFilteredResult{Path: tc.path, Score: 0.5}
+			// The highlights do not change the text.
+			if row := m.renderRow(result, tc.current, []int{0, 4, 5, 10}); row != tc.expected {
+				t.Errorf("Expected %q, obtained %q", tc.expected, row)
+			}
+		})
+	}
+}
@@ -2,8 +2,34 @@ package tui
 
 import (
 	"jetfind/internal/scanengine"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type errMsg error
 type newPathMsg scanengine.ScanFilteredResult
 type scanDoneMsg struct{}
+
+// scanMsg is a message of the scan started with generation gen.
+type scanMsg struct {
+	gen int
+	msg tea.Msg
+}
+
+type filterDoneMsg struct {
+	gen     int
+	results []scanengine.ScanFilteredResult
+	total   int
+	err     error
+}
+
+type spinnerTickMsg struct{}
+
+// previewMsg is the preview loaded by the request with generation gen.
+type previewMsg struct {
+	gen  int
+	text string
+}
+
+// noticeMsg is a short message shown in the status line until the next key.
+type noticeMsg string
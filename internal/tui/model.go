@@ -1,59 +1,172 @@
 package tui
 
 import (
+	"context"
 	"jetfind/internal/config"
-	"jetfind/internal/findignore"
+	"jetfind/internal/frecency"
+	"jetfind/internal/history"
+	"jetfind/internal/keymap"
 	"jetfind/internal/scanengine"
 	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const scanRoot = "./"
+
 type Model struct {
-	cfg             *config.Config
-	userQuery       string
-	scanChan        <-chan string
-	scannedPaths    []scanengine.ScanFilteredResult
-	filteredPaths   []scanengine.ScanFilteredResult
-	filterRequested bool
+	cfg       *config.Config
+	userQuery queryInput
+	scanChan  <-chan string
+	// Incremented when the scan restarts, to discard the paths of the
+	// previous one.
+	scanGen       int
+	scannedPaths  []scanengine.ScanFilteredResult
+	filteredPaths []scanengine.ScanFilteredResult
+	matchCount    int
+	filterGen     int
+	// Number of scanned paths given to the last filter job.
+	filteredScanned int
+	filterCancel    context.CancelFunc
+	filtering       bool
+	spinning        bool
+	spinnerFrame    int
 	scanDone        bool
 	scanErr         error
 	cursor          int
 	offset          int
 	height          int
-	SelectedFile    string
+	width           int
+	keys            keymap.Keymap
+	marks           []scanengine.ScanFilteredResult
+	preview         bool
+	previewPath     string
+	previewText     string
+	previewGen      int
+	showHelp        bool
+	notice          string
+	quitting        bool
+	dirs            bool
+	aborted         bool
+	selectOne       bool
+	exitZero        bool
+	autoSelected    bool
+	Selected        []scanengine.ScanFilteredResult
+
+	history        *history.History
+	historyErr     error
+	frecency       *frecency.Store
+	root           string
+	historyPos     int
+	historyQueries []string
+	savedQuery     string
+	searching      bool
+	searchTerm     string
+	searchMatch    int
+
+	lastClickRow  int
+	lastClickTime time.Time
+
+	// reload loads the configuration again when one of the watched files
+	// changes. Nothing is watched when it is nil.
+	reload    func() (*config.Config, error)
+	watched   []string
+	modTimes  map[string]time.Time
+	reloadErr string
+	// keysErr is why the configured key bindings are ignored.
+	keysErr error
+	// tuned holds the filter settings changed from the TUI.
+	tuned map[string]bool
 }
 
-func NewModel(cfg *config.Config) *Model {
+func NewModel(cfg *config.Config, hist *history.History, store *frecency.Store) *Model {
+	root, err := filepath.Abs(scanRoot)
+	if err != nil {
+		root = scanRoot
+	}
+
+	keys, keysErr := newKeymap(cfg)
 	return &Model{
 		cfg:          cfg,
+		keys:         keys,
+		keysErr:      keysErr,
 		scannedPaths: []scanengine.ScanFilteredResult{},
 		cursor:       0,
 		offset:       0,
+		history:      hist,
+		frecency:     store,
+		root:         root,
+		historyPos:   -1,
+		searchMatch:  -1,
+		lastClickRow: -1,
+	}
+}
+
+// newKeymap returns the default key bindings with the configured ones, or
+// the default ones alone with the error when the configured ones are
+// invalid.
+func newKeymap(cfg *config.Config) (keymap.Keymap, error) {
+	keys := keymap.Default()
+	bindings, err := keymap.ParseBindings(cfg.Tui.Keys)
+	if err != nil {
+		return keys, err
 	}
+	keys.Bind(bindings)
+	return keys, nil
 }
 
 func (m *Model) Init() tea.Cmd {
-	var fi *findingnore.FindIgnore
-	var err error
-	cfgDir := config.GetConfigDir()
-	if m.cfg.Findignore.Enable {
-		fi, err = findingnore.New(filepath.Join(cfgDir, ".findignore"), true)
-		if err != nil {
-			return func() tea.Msg {
-				return errMsg(err)
+	scan := m.startScan()
+	if m.reload == nil || len(m.watched) == 0 {
+		return scan
+	}
+	m.modTimes = modTimes(m.watched)
+	return tea.Batch(scan, watchCmd(m.watched))
+}
+
+// startScan scans the roots, dropping the paths of a previous scan.
+func (m *Model) startScan() tea.Cmd {
+	if m.scanChan != nil && !m.scanDone {
+		// The workers of the previous scan stop once it is drained.
+		go func(ch <-chan string) {
+			for range ch {
 			}
+		}(m.scanChan)
+	}
+	m.scanGen++
+	m.scanDone = false
+	m.scanErr = nil
+	m.scannedPaths = []scanengine.ScanFilteredResult{}
+	m.filteredPaths = m.scannedPaths
+	m.matchCount = 0
+	m.cancelFiltering()
+	m.filterGen++
+
+	fi, err := m.cfg.Findignore.FindIgnore()
+	if err != nil {
+		return func() tea.Msg {
+			return errMsg(err)
 		}
-	} else {
-		fi = nil
 	}
 
 	scanCfg := scanengine.Config{
-		Root:       "./",
+		Root:       scanRoot,
+		Roots:      m.cfg.ScanRoots(),
 		FindIgnore: fi,
+		Dirs:       m.dirs,
 	}
 
 	scanner := scanengine.New(scanCfg)
 	m.scanChan = scanner.Run()
-	return popFromScanChanCmd(m.scanChan)
+	return m.nextPath()
+}
+
+// absPath returns the absolute path of a scanned path, which is relative to
+// the current directory unless it comes from a configured root.
+func (m *Model) absPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.root, path)
 }
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"jetfind/internal/shellquote"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Only the beginning of a file is read for the preview.
+const previewMaxBytes = 16 * 1024
+
+// A preview command is killed after this long. Its output is then waited
+// for previewWaitDelay at most, in case a background process keeps it open.
+const (
+	previewTimeout   = 2 * time.Second
+	previewWaitDelay = 100 * time.Millisecond
+)
+
+// readPreview returns the beginning of the file at path, or a short note
+// when it cannot be shown as text. Only regular files are read: opening a
+// FIFO would block.
+func readPreview(path string) string {
+	info, err := os.Lstat(path)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		info, err = os.Stat(path)
+	}
+	if err != nil {
+		return err.Error()
+	}
+	if !info.Mode().IsRegular() {
+		return "(not a regular file)"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err.Error()
+	}
+	defer f.Close()
+
+	buf := make([]byte, previewMaxBytes)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err.Error()
+	}
+	if bytes.IndexByte(buf[:n], 0) >= 0 {
+		return "(binary file)"
+	}
+	return strings.ReplaceAll(string(buf[:n]), "\t", "    ")
+}
+
+// runPreview returns the output of command, run in dir with {} replaced by
+// path. It runs in the background, see requestPreview.
+func runPreview(command, dir, path string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", strings.ReplaceAll(command, "{}", shellquote.Posix(path)))
+	cmd.Dir = dir
+	cmd.WaitDelay = previewWaitDelay
+	out, err := cmd.CombinedOutput()
+	if len(out) > previewMaxBytes {
+		out = out[:previewMaxBytes]
+	}
+	text := strings.ReplaceAll(string(out), "\t", "    ")
+	if err != nil {
+		text += err.Error()
+	}
+	return text
+}
+
+// requestPreview loads the preview of the highlighted file in the
+// background when the preview is shown and the file changed. The results
+// of older requests are discarded on arrival.
+func (m *Model) requestPreview() tea.Cmd {
+	path, ok := m.highlighted()
+	if !m.preview || !ok || path == m.previewPath {
+		return nil
+	}
+	m.previewPath = path
+	m.previewText = ""
+	m.previewGen++
+
+	gen, command, dir, abs := m.previewGen, m.cfg.Preview.Command, m.root, m.absPath(path)
+	return func() tea.Msg {
+		if command != "" {
+			return previewMsg{gen: gen, text: runPreview(command, dir, path)}
+		}
+		return previewMsg{gen: gen, text: readPreview(abs)}
+	}
+}
+
+// previewLines returns at most n lines of the last loaded preview.
+func (m *Model) previewLines(n int) []string {
+	if _, ok := m.highlighted(); !ok {
+		return nil
+	}
+	lines := strings.Split(m.previewText, "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return lines
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"jetfind/internal/config"
+	"jetfind/internal/scanengine"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadPreview(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	text := write("main.go", "package main\n\tfunc main() {}\n")
+	link := filepath.Join(dir, "link.go")
+	if err := os.Symlink(text, link); err != nil {
+		t.Fatal(err)
+	}
+	fifo := filepath.Join(dir, "pipe")
+	if err := exec.Command("mkfifo", fifo).Run(); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "text", path: text, expected: "package main\n    func main() {}\n"},
+		{name: "binary", path: write("app", "ELF\x00"), expected: "(binary file)"},
+		{name: "symlink", path: link, expected: "package main\n    func main() {}\n"},
+		{name: "directory", path: dir, expected: "(not a regular file)"},
+		{name: "fifo", path: fifo, expected: "(not a regular file)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			done := make(chan string)
+			go func() { done <- readPreview(tc.path) }()
+			select {
+			case text := <-done:
+				if text != tc.expected {
+					t.Errorf("Expected %q, obtained %q", tc.expected, text)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("readPreview blocked")
+			}
+		})
+	}
+}
+
+func TestPreviewLoadedInBackground(t *testing.T) {
+	DefaultStyles()
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("content of "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := *config.Default
+	m := NewModel(&cfg, nil, nil)
+	m.root = dir
+	m.filteredPaths = []scanengine.ScanFilteredResult{{Path: "a"}, {Path: "b"}}
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+
+	_, load := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}, Alt: true})
+	if load == nil {
+		t.Fatal("Expected the preview to be loaded")
+	}
+	m.View()
+	if m.previewText != "" {
+		t.Fatalf("Expected the view not to load the preview, obtained %q", m.previewText)
+	}
+	stale := load()
+
+	_, load = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if load == nil {
+		t.Fatal("Expected the preview of the new file to be loaded")
+	}
+	m.Update(load())
+	m.Update(stale)
+	if m.previewText != "content of b" {
+		t.Errorf("Expected the preview of b, obtained %q", m.previewText)
+	}
+
+	// Redraws and other messages use the loaded preview.
+	if _, cmd := m.Update(spinnerTickMsg{}); cmd != nil {
+		t.Error("Expected the preview not to be loaded again")
+	}
+}
+
+func TestRunPreview(t *testing.T) {
+	dir := t.TempDir()
+	if text := runPreview("printf '%s\\t|' {}", dir, "it's.go"); text != "it's.go    |" {
+		t.Errorf("Expected the quoted path, obtained %q", text)
+	}
+
+	// A background process keeping the output open does not hold the
+	// preview past the timeout.
+	start := time.Now()
+	runPreview("sleep 10 & echo started", dir, "a")
+	if elapsed := time.Since(start); elapsed > previewTimeout+time.Second {
+		t.Errorf("Expected the preview to stop after %v, obtained %v", previewTimeout, elapsed)
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"jetfind/internal/config"
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the configuration files are checked for
+// changes.
+const watchInterval = time.Second
+
+// watchMsg holds the modification times of the watched files.
+type watchMsg map[string]time.Time
+
+// modTimes returns the modification times of files, the zero time for the
+// missing ones.
+func modTimes(files []string) map[string]time.Time {
+	times := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		var t time.Time
+		if info, err := os.Stat(file); err == nil {
+			t = info.ModTime()
+		}
+		times[file] = t
+	}
+	return times
+}
+
+func watchCmd(files []string) tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchMsg(modTimes(files))
+	})
+}
+
+// updateWatch reloads the configuration when a watched file changed, and
+// keeps watching.
+func (m *Model) updateWatch(times watchMsg) tea.Cmd {
+	watch := watchCmd(m.watched)
+	if maps.Equal(times, m.modTimes) {
+		return watch
+	}
+	findignore := config.GetFindIgnorePath()
+	rescan := !times[findignore].Equal(m.modTimes[findignore])
+	m.modTimes = times
+
+	cfg, err := m.reload()
+	if err != nil {
+		// The first problem fits in the status line.
+		m.reloadErr, _, _ = strings.Cut(err.Error(), "\n")
+		return watch
+	}
+	m.reloadErr = ""
+	return tea.Batch(watch, m.applyConfig(cfg, rescan))
+}
+
+// applyConfig switches to cfg. The styles, key bindings and filter settings
+// apply to the current results, while a change of the ignore rules or of
+// the roots, or rescan, starts the scan again. The filter settings changed
+// from the TUI are kept, and so are the settings only read on start.
+func (m *Model) applyConfig(cfg *config.Config, rescan bool) tea.Cmd {
+	old := m.cfg
+	keepTuned(m.tuned, old, cfg)
+	cfg.Tui.Height = old.Tui.Height
+	cfg.Tui.Layout = old.Tui.Layout
+	cfg.History = old.History
+	cfg.Frecency = old.Frecency
+	m.cfg = cfg
+	ConfiguredStyles(cfg.Tui)
+	m.keys, m.keysErr = newKeymap(cfg)
+	// The preview command may have changed.
+	m.previewPath = ""
+
+	if rescan || !reflect.DeepEqual(old.Findignore, cfg.Findignore) || !reflect.DeepEqual(old.Roots, cfg.Roots) {
+		return m.startScan()
+	}
+	return m.requestFiltering()
+}
+
+// keepTuned copies the tuned filter settings of old to cfg.
+func keepTuned(tuned map[string]bool, old, cfg *config.Config) {
+	for setting := range tuned {
+		switch setting {
+		case "filter.type":
+			cfg.Filter.Type = old.Filter.Type
+		case "filter.algorithm":
+			cfg.Filter.Algo = old.Filter.Algo
+		case "filter.threshold":
+			cfg.Filter.Threshold = old.Filter.Threshold
+		case "filter.case":
+			cfg.Filter.Case = old.Filter.Case
+		case "filter.scope":
+			cfg.Filter.Scope = old.Filter.Scope
+		}
+	}
+}
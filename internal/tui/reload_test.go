@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"jetfind/internal/config"
+	"jetfind/internal/scanengine"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReloadConfig(t *testing.T) {
+	DefaultStyles()
+	cfg := *config.Default
+	m := NewModel(&cfg, nil, nil)
+	m.userQuery.Set("a")
+	m.scanDone = true
+	m.scannedPaths = []scanengine.ScanFilteredResult{{Path: "a"}, {Path: "b"}}
+	m.watched = []string{"config.yml"}
+	m.modTimes = map[string]time.Time{"config.yml": {}}
+
+	reloaded := *config.Default
+	reloaded.Filter.Type = scanengine.TypeContains
+	reloaded.Tui.Keys = map[string]string{"ctrl-y": "accept"}
+	var err error
+	m.reload = func() (*config.Config, error) { return &reloaded, err }
+
+	// Nothing changed.
+	m.Update(watchMsg{"config.yml": {}})
+	if m.cfg != &cfg {
+		t.Fatal("Expected the configuration to be kept while the files do not change")
+	}
+
+	err = errors.New("config.yml:2:9: filter.type: invalid filter type: regex\nconfig.yml:3:3: unknown key")
+	m.Update(watchMsg{"config.yml": time.Unix(1, 0)})
+	if m.cfg != &cfg || m.reloadErr != "config.yml:2:9: filter.type: invalid filter type: regex" {
+		t.Fatalf("Expected the first problem to be reported, obtained %q", m.reloadErr)
+	}
+	if view := m.View(); !strings.Contains(view, "Configuration not reloaded") {
+		t.Errorf("Expected the problem in the status line, obtained %q", view)
+	}
+
+	err = nil
+	filterGen := m.filterGen
+	m.Update(watchMsg{"config.yml": time.Unix(2, 0)})
+	if m.cfg != &reloaded || m.reloadErr != "" {
+		t.Fatalf("Expected the new configuration, obtained %+v (%q)", m.cfg.Filter, m.reloadErr)
+	}
+	if _, ok := m.keys["ctrl+y"]; !ok {
+		t.Error("Expected the new key bindings")
+	}
+	// The results are filtered again, without scanning again.
+	if !m.filtering || m.filterGen != filterGen+1 || len(m.scannedPaths) != 2 {
+		t.Errorf("Expected the scanned paths to be filtered again, obtained generation %d over %v", m.filterGen, paths(m.scannedPaths))
+	}
+}
+
+func TestReloadKeepsTunedFilter(t *testing.T) {
+	DefaultStyles()
+	cfg := *config.Default
+	m := NewModel(&cfg, nil, nil)
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m.Update(tea.KeyMsg{Type: tea.KeyDown, Alt: true})
+	tuned := m.cfg.Filter
+
+	reloaded := *config.Default
+	reloaded.Filter.Case = scanengine.CaseRespect
+	reloaded.Filter.Threshold = 0.5
+	reloaded.Tui.Theme = config.ThemeLight
+	m.applyConfig(&reloaded, false)
+
+	expected := tuned
+	expected.Case = scanengine.CaseRespect
+	if !reflect.DeepEqual(m.cfg.Filter, expected) {
+		t.Errorf("Expected the tuned filter with the new case %+v, obtained %+v", expected, m.cfg.Filter)
+	}
+}
+
+func TestReloadKeepsStartSettings(t *testing.T) {
+	DefaultStyles()
+	cfg := *config.Default
+	cfg.Tui.Height = "40%"
+	cfg.Tui.Layout = config.LayoutReverse
+	m := NewModel(&cfg, nil, nil)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	height := m.height
+
+	reloaded := *config.Default
+	reloaded.Tui.Layout = config.LayoutDefault
+	reloaded.History.Size = 5
+	reloaded.Frecency.Enable = true
+	reloaded.Tui.Theme = config.ThemeLight
+	m.applyConfig(&reloaded, false)
+
+	if m.cfg.Tui.Height != "40%" || m.cfg.Tui.Layout != config.LayoutReverse || m.height != height {
+		t.Errorf("Expected the reverse layout at 40%% to stay, obtained %q, %q and height %d", m.cfg.Tui.Layout, m.cfg.Tui.Height, m.height)
+	}
+	if m.cfg.History != cfg.History || m.cfg.Frecency != cfg.Frecency {
+		t.Errorf("Expected the history and frecency settings to stay, obtained %+v and %+v", m.cfg.History, m.cfg.Frecency)
+	}
+	if m.cfg.Tui.Theme != config.ThemeLight {
+		t.Errorf("Expected the new theme, obtained %q", m.cfg.Tui.Theme)
+	}
+	if view := m.View(); strings.Index(view, "Search") < strings.Index(view, "Scanning") {
+		t.Errorf("Expected the query box below the results, obtained %q", view)
+	}
+}
+
+func TestReloadIgnoreRulesRescans(t *testing.T) {
+	root := t.TempDir()
+	cfg := *config.Default
+	cfg.Roots = []string{root}
+	m := NewModel(&cfg, nil, nil)
+	m.Init()
+	stale := m.scanGen
+	m.Update(newPathMsg(scanengine.ScanFilteredResult{Path: "old"}))
+
+	reloaded := cfg
+	reloaded.Findignore.Patterns = []string{"*.log"}
+	m.applyConfig(&reloaded, false)
+	if m.scanGen != stale+1 || len(m.scannedPaths) != 0 || m.scanDone {
+		t.Fatalf("Expected the scan to start again, obtained generation %d with %v", m.scanGen, paths(m.scannedPaths))
+	}
+
+	// The paths of the previous scan are dropped.
+	m.Update(scanMsg{gen: stale, msg: newPathMsg(scanengine.ScanFilteredResult{Path: "old"})})
+	m.Update(scanMsg{gen: m.scanGen, msg: newPathMsg(scanengine.ScanFilteredResult{Path: "new"})})
+	if !reflect.DeepEqual(paths(m.scannedPaths), []string{"new"}) {
+		t.Errorf("Expected only the paths of the new scan, obtained %v", paths(m.scannedPaths))
+	}
+}
@@ -1,53 +1,74 @@
 package tui
 
 import (
-	"github.com/charmbracelet/lipgloss"
-)
+	"jetfind/internal/config"
 
-var (
-	HighlightedFileStyle lipgloss.Style
-	QueryBoxStyle        lipgloss.Style
-	SeparatorStyle       lipgloss.Style
-	StatusStyle          lipgloss.Style
+	"github.com/charmbracelet/lipgloss"
 )
 
-func DefaultStyles() {
-
-	HighlightedFileStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Bold(true).
-		Padding(0, 1)
-
-	StatusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Italic(true)
+// Theme holds the style of every element of the TUI.
+type Theme struct {
+	Prompt    lipgloss.Style
+	QueryBox  lipgloss.Style
+	Cursor    lipgloss.Style
+	Separator lipgloss.Style
+	CursorRow lipgloss.Style
+	Marked    lipgloss.Style
+	Match     lipgloss.Style
+	Score     lipgloss.Style
+	Status    lipgloss.Style
+	Preview   lipgloss.Style
+	Error     lipgloss.Style
+}
 
-	QueryBoxStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#F9FAFB")).
-		Background(lipgloss.Color("#374151")).
-		Padding(0, 1).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#6B7280"))
+// Styles is the theme the TUI is drawn with.
+var Styles Theme
 
-	SeparatorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4B5563")).
-		Bold(true)
+// NewTheme builds the styles of a theme from its colors. The attributes do
+// not depend on the colors, so that the no-color theme stays readable.
+func NewTheme(colors config.ThemeColors) Theme {
+	return Theme{
+		Prompt: lipgloss.NewStyle().Foreground(color(colors.Prompt)).Bold(true),
+		QueryBox: lipgloss.NewStyle().
+			Foreground(color(colors.QueryText)).
+			Background(color(colors.QueryBackground)).
+			Padding(0, 1).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color(colors.QueryBorder)),
+		Cursor:    lipgloss.NewStyle().Reverse(true),
+		Separator: lipgloss.NewStyle().Foreground(color(colors.Separator)).Bold(true),
+		CursorRow: lipgloss.NewStyle().
+			Foreground(color(colors.CursorRow)).
+			Background(color(colors.CursorRowBackground)).
+			Bold(true),
+		Marked: lipgloss.NewStyle().Foreground(color(colors.Marked)),
+		Match:  lipgloss.NewStyle().Foreground(color(colors.Match)).Underline(true),
+		Score:  lipgloss.NewStyle().Foreground(color(colors.Score)).Faint(colors.Score.IsZero()),
+		Status: lipgloss.NewStyle().Foreground(color(colors.Status)).Italic(true),
+		Preview: lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(color(colors.PreviewBorder)).
+			PaddingLeft(1),
+		Error: lipgloss.NewStyle().Foreground(color(colors.Error)).Bold(true),
+	}
 }
 
-func ConfiguredStyles(hfForeground, qbTextForeground, qbTextBackground, qbBorderForeground string) {
-	HighlightedFileStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color(hfForeground)).
-		Bold(true).
-		Padding(0, 1)
-
-	StatusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#9CA3AF")).Italic(true)
+// color returns the terminal color of c, adapting to the background of the
+// terminal when c has a color for each.
+func color(c config.Color) lipgloss.TerminalColor {
+	switch {
+	case c.IsZero():
+		return lipgloss.NoColor{}
+	case c.Light == c.Dark:
+		return lipgloss.Color(config.ANSI(c.Light))
+	}
+	return lipgloss.AdaptiveColor{Light: config.ANSI(c.Light), Dark: config.ANSI(c.Dark)}
+}
 
-	QueryBoxStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color(qbTextForeground)).
-		Background(lipgloss.Color(qbTextBackground)).
-		Padding(0, 1).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(qbBorderForeground))
+func DefaultStyles() {
+	Styles = NewTheme(config.Themes[config.ThemeDark])
+}
 
-	SeparatorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4B5563")).
-		Bold(true)
+func ConfiguredStyles(tui config.TuiConfig) {
+	Styles = NewTheme(tui.ThemeColors())
 }
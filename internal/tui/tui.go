@@ -1,37 +1,112 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"jetfind/internal/config"
+	"jetfind/internal/frecency"
+	"jetfind/internal/history"
+	"jetfind/internal/scanengine"
+	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 )
 
-func Run() (string, error) {
-	cfg := config.LoadOrDefault()
-	ConfiguredStyles(
-		cfg.Tui.HighlightedFile.Foreground,
-		cfg.Tui.QueryBox.TextForeground,
-		cfg.Tui.QueryBox.TextBackground,
-		cfg.Tui.QueryBox.BorderForeground,
-	)
+// Options are the settings of a single run that do not come from the
+// configuration.
+type Options struct {
+	// Dirs lists directories instead of files.
+	Dirs bool
+	// SelectOne accepts the only match without waiting for the user.
+	SelectOne bool
+	// ExitZero quits at once when nothing matches.
+	ExitZero bool
+	// Query is typed in the query box on start.
+	Query string
+	// Reload loads the configuration again when one of the Watch files
+	// changes, to apply it without restarting.
+	Reload func() (*config.Config, error)
+	Watch  []string
+}
+
+// ErrAborted is returned by Run when the user quits with ctrl+c or esc.
+var ErrAborted = errors.New("aborted")
 
-	model := NewModel(cfg)
+// Run starts the TUI and returns the selected results, empty when the user
+// accepts without any match. It returns ErrAborted when the user quits.
+func Run(cfg *config.Config, opts Options) ([]scanengine.ScanFilteredResult, error) {
+	var programOpts []tea.ProgramOption
+	// When the selection is captured, e.g. by $(jetfind), the TUI is drawn
+	// on stderr.
+	if !term.IsTerminal(os.Stdout.Fd()) {
+		programOpts = append(programOpts, tea.WithOutput(os.Stderr))
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(os.Stderr))
+	}
 
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	ConfiguredStyles(cfg.Tui)
+
+	var hist *history.History
+	if cfg.History.Size > 0 {
+		var err error
+		hist, err = history.Load(cfg.History.GetHistoryFilePath(), cfg.History.Size)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: query history disabled: %v\n", err)
+		}
+	}
+
+	// Selections are always recorded; frecency.enable only decides whether
+	// they boost the ranking.
+	store, err := frecency.Load(cfg.Frecency.GetFrecencyFilePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: selections not recorded: %v\n", err)
+	}
+
+	model := NewModel(cfg, hist, store)
+	model.dirs = opts.Dirs
+	model.selectOne = opts.SelectOne
+	model.exitZero = opts.ExitZero
+	// The initial query is not an undo step.
+	model.userQuery = newQueryInput(opts.Query)
+	model.reload = opts.Reload
+	model.watched = opts.Watch
+
+	// Mouse coordinates are relative to the screen, so the mouse is only
+	// enabled when the TUI takes over the whole screen.
+	if cfg.Tui.Height == "" {
+		programOpts = append(programOpts, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+
+	p := tea.NewProgram(model, programOpts...)
 
 	finalModel, err := p.Run()
+	if errors.Is(err, tea.ErrInterrupted) {
+		return nil, ErrAborted
+	}
 	if err != nil {
-		return "", fmt.Errorf("TUI error: %w", err)
+		return nil, fmt.Errorf("TUI error: %w", err)
 	}
 
 	if m, ok := finalModel.(*Model); ok {
-		return m.SelectedFile, nil
+		if m.historyErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save query history: %v\n", m.historyErr)
+		}
+		if store != nil && len(m.Selected) > 0 {
+			now := time.Now()
+			for _, r := range m.Selected {
+				store.Record(m.absPath(r.Path), now)
+			}
+			if err := store.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save frecency data: %v\n", err)
+			}
+		}
+		if m.aborted {
+			return nil, ErrAborted
+		}
+		return m.Selected, nil
 	}
 
-	return "", nil
+	return nil, nil
 }
@@ -1,75 +1,222 @@
 package tui
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"context"
 	"jetfind/internal/scanengine"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
-func popFromScanChanCmd(ch <-chan string) tea.Cmd {
+const spinnerInterval = 100 * time.Millisecond
+
+// Upper bound on the results kept by a filter job; the list only ever
+// renders one screen of them.
+const maxFilteredResults = 1000
+
+func popFromScanChanCmd(ch <-chan string, gen int) tea.Cmd {
 	return func() tea.Msg {
 		if p, ok := <-ch; ok {
-			return newPathMsg(scanengine.ScanFilteredResult{Path: p, Score: 1.0})
+			return scanMsg{gen: gen, msg: newPathMsg(scanengine.ScanFilteredResult{Path: p, Score: 1.0})}
 		}
-		return scanDoneMsg{}
+		return scanMsg{gen: gen, msg: scanDoneMsg{}}
+	}
+}
+
+// nextPath waits for the next path of the current scan.
+func (m *Model) nextPath() tea.Cmd {
+	return popFromScanChanCmd(m.scanChan, m.scanGen)
+}
+
+func filterCmd(ctx context.Context, gen int, paths []scanengine.ScanFilteredResult, scanFilter scanengine.ScanFilter, ranking scanengine.Ranking) tea.Cmd {
+	return func() tea.Msg {
+		results, total, err := scanengine.FilterEngineTopK(ctx, paths, scanFilter, ranking, maxFilteredResults)
+		return filterDoneMsg{gen: gen, results: results, total: total, err: err}
 	}
 }
 
+func spinnerTickCmd() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+func (m *Model) newScanFilter() scanengine.ScanFilter {
+	return scanengine.NewScanFilter(m.cfg.ScanFilterOptions(m.root), m.userQuery.String())
+}
+
+// requestFiltering supersedes any in-flight filter job and dispatches a new
+// one for the current query. Results of older jobs are discarded on arrival.
+func (m *Model) requestFiltering() tea.Cmd {
+	m.cancelFiltering()
+	m.filterGen++
+
+	if m.userQuery.IsEmpty() {
+		m.filteredPaths = m.scannedPaths
+		m.matchCount = len(m.scannedPaths)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.filterCancel = cancel
+	m.filtering = true
+
+	m.filteredScanned = len(m.scannedPaths)
+	cmd := filterCmd(ctx, m.filterGen, m.scannedPaths, m.newScanFilter(), m.ranking())
+	if m.spinning {
+		return cmd
+	}
+	m.spinning = true
+	return tea.Batch(cmd, spinnerTickCmd())
+}
+
+func (m *Model) caseMode() string {
+	if m.cfg.Filter.Case == "" {
+		return scanengine.CaseIgnore
+	}
+	return m.cfg.Filter.Case
+}
+
+func (m *Model) scope() string {
+	if m.cfg.Filter.Scope == "" {
+		return scanengine.ScopeAuto
+	}
+	return m.cfg.Filter.Scope
+}
+
+func (m *Model) ranking() scanengine.Ranking {
+	var boost func(string) float64
+	if m.frecency != nil {
+		boost = m.frecency.Boost(m.root, time.Now())
+	}
+	return m.cfg.Ranking(boost)
+}
+
+func (m *Model) recordQuery() {
+	if m.history != nil {
+		m.historyErr = m.history.Add(m.userQuery.String(), m.root)
+	}
+}
+
+// autoSelect applies --select-1 and --exit-0 once, when the scan and the
+// filtering of the query are complete.
+func (m *Model) autoSelect() tea.Cmd {
+	if m.autoSelected || !m.scanDone || m.filtering {
+		return nil
+	}
+	m.autoSelected = true
+
+	switch {
+	case m.selectOne && m.matchCount == 1:
+		return m.accept()
+	case m.exitZero && m.matchCount == 0:
+		m.quitting = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *Model) cancelFiltering() {
+	if m.filterCancel != nil {
+		m.filterCancel()
+		m.filterCancel = nil
+	}
+	m.filtering = false
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	_, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.requestPreview())
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case scanMsg:
+		if msg.gen != m.scanGen {
+			return m, nil
+		}
+		return m.update(msg.msg)
 	case newPathMsg:
 		m.scannedPaths = append(m.scannedPaths, scanengine.ScanFilteredResult(msg))
-		return m, popFromScanChanCmd(m.scanChan)
+		if m.userQuery.IsEmpty() {
+			m.filteredPaths = m.scannedPaths
+			m.matchCount = len(m.scannedPaths)
+		} else if !m.filtering {
+			return m, tea.Batch(m.nextPath(), m.requestFiltering())
+		}
+		return m, m.nextPath()
 	case errMsg:
 		m.scanErr = msg
 		return m, nil
 	case scanDoneMsg:
 		m.scanDone = true
+		if !m.userQuery.IsEmpty() {
+			return m, m.requestFiltering()
+		}
+		return m, m.autoSelect()
+	case filterDoneMsg:
+		if msg.gen != m.filterGen || msg.err != nil {
+			return m, nil
+		}
+		m.filteredPaths = msg.results
+		m.matchCount = msg.total
+		m.filterCancel = nil
+		m.filtering = false
+		m.setCursor(m.cursor)
+		// Paths scanned while the job was running are filtered by the
+		// next one.
+		if !m.scanDone && m.filteredScanned < len(m.scannedPaths) {
+			return m, m.requestFiltering()
+		}
+		return m, m.autoSelect()
+	case previewMsg:
+		if msg.gen == m.previewGen {
+			m.previewText = msg.text
+		}
 		return m, nil
+	case spinnerTickMsg:
+		if !m.filtering {
+			m.spinning = false
+			return m, nil
+		}
+		m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+		return m, spinnerTickCmd()
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
-			return m, tea.Quit
-		case "enter":
-			if len(m.filteredPaths) > 0 && m.cursor < len(m.filteredPaths) {
-				m.SelectedFile = m.filteredPaths[m.cursor].Path
-				return m, tea.Quit
-			}
-		case "up":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down":
-			if m.cursor < len(m.filteredPaths)-1 {
-				m.cursor++
-			}
-		case "backspace":
-			if m.userQuery != "" {
-				m.userQuery = m.userQuery[:len(m.userQuery)-1]
-			}
-			if m.userQuery != "" {
-				m.filterRequested = true
-				m.cursor = 0
-			}
+		if m.searching {
+			return m, m.updateHistorySearch(msg)
 		}
 
-		if msg.Type == tea.KeyRunes {
-			m.userQuery += string(msg.Runes)
-			m.filterRequested = true
-			m.cursor = 0
+		m.notice = ""
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
 		}
 
-		visibleLines := m.height - 4
+		if actions, ok := m.keys[msg.String()]; ok {
+			return m, m.runActions(actions)
+		}
 
-		if m.cursor < m.offset {
-			m.offset = m.cursor
+		var runes []rune
+		if (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt {
+			runes = msg.Runes
 		}
-		if m.cursor >= m.offset+visibleLines {
-			m.offset = m.cursor - visibleLines + 1
+		if _, changed := m.userQuery.HandleKey(msg.String(), runes); changed {
+			m.historyPos = -1
+			return m, m.queryChanged()
 		}
 		return m, nil
+	case watchMsg:
+		return m, m.updateWatch(msg)
+	case noticeMsg:
+		m.notice = string(msg)
+		return m, nil
+	case tea.MouseMsg:
+		return m, m.updateMouse(msg)
 	case tea.WindowSizeMsg:
-		m.height = msg.Height - 1
+		m.width = msg.Width
+		m.height = m.viewHeight(msg.Height) - footerLines
+		m.setCursor(m.cursor)
 		return m, nil
 	}
 	return m, nil
@@ -2,90 +2,213 @@ package tui
 
 import (
 	"fmt"
+	"jetfind/internal/keymap"
 	"jetfind/internal/scanengine"
+	"slices"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
 func (m *Model) View() string {
+	if m.quitting {
+		return ""
+	}
 	if m.scanErr != nil {
-		return fmt.Sprintf("Error: %v\n", m.scanErr)
+		return Styles.Error.Render(fmt.Sprintf("Error: %v", m.scanErr)) + "\n"
 	}
 
-	m.applyFiltering()
-
 	var b strings.Builder
 
+	if m.reverse() {
+		m.renderStatus(&b)
+		b.WriteString("\n")
+		m.renderBody(&b)
+		m.renderSeparator(&b)
+		m.renderQueryBox(&b)
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+
 	m.renderQueryBox(&b)
 	m.renderSeparator(&b)
-	m.renderPathList(&b)
+	m.renderBody(&b)
 	m.renderStatus(&b)
 
 	return b.String()
 }
 
-func (m *Model) applyFiltering() {
-	if m.userQuery == "" {
-		m.filteredPaths = m.scannedPaths
+// renderBody renders the results, or the help. When the TUI does not fill
+// the screen, the body is padded so the layout does not jump as the number
+// of results changes. In the reverse layout the best result is at the
+// bottom, next to the query.
+func (m *Model) renderBody(b *strings.Builder) {
+	var rows []string
+	if m.showHelp {
+		rows = m.helpRows()
 	} else {
-		var scanFilter scanengine.ScanFilter
-		if m.filterRequested {
-			switch m.cfg.Filter.Type {
-			case "null":
-				scanFilter = scanengine.NoFilter{Pattern: m.userQuery}
-			case "contains":
-				scanFilter = scanengine.ContainsFilter{Pattern: m.userQuery}
-			case "fuzzy":
-				scanFilter = scanengine.FuzzyFilter{
-					Pattern:    m.userQuery,
-					Algo:       m.cfg.Filter.Algo,
-					Threashold: m.cfg.Filter.Threashold,
-				}
-
-			}
-			m.filteredPaths = scanengine.FilterEngine(m.scannedPaths, scanFilter)
-			m.filterRequested = false
+		rows = m.pathRows()
+	}
+
+	if m.inline() || m.reverse() {
+		for len(rows) < m.visibleRows() {
+			rows = append(rows, "")
 		}
 	}
+	if m.reverse() {
+		slices.Reverse(rows)
+	}
+
+	if !m.preview || m.showHelp || m.width <= 0 || len(rows) == 0 {
+		for _, row := range rows {
+			b.WriteString(row + "\n")
+		}
+		return
+	}
+
+	listWidth := m.width / 2
+	list := lipgloss.NewStyle().Width(listWidth).MaxWidth(listWidth).Render(strings.Join(rows, "\n"))
+	previewWidth := m.width - listWidth
+	preview := Styles.Preview.MaxWidth(previewWidth).Render(strings.Join(m.previewLines(m.visibleRows()), "\n"))
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list, preview) + "\n")
 }
 
 func (m *Model) renderQueryBox(b *strings.Builder) {
-	queryText := "Search: " + m.userQuery
-	if m.userQuery == "" {
-		queryText = "Search: (type to search...)"
+	if m.searching {
+		searchText := fmt.Sprintf("(reverse-search) '%s'%s: %s", m.searchTerm, Styles.Cursor.Render(" "), m.searchMatchQuery())
+		b.WriteString(Styles.QueryBox.Render(searchText) + "\n")
+		return
+	}
+
+	filter := m.filterMode().String()
+	if m.cfg.Filter.Type == scanengine.TypeFuzzy {
+		filter += fmt.Sprintf(", threshold %.2f", m.cfg.Filter.Threshold)
+	}
+	prompt := Styles.Prompt.Render(fmt.Sprintf("Search [%s, %s, %s]:", filter, m.caseMode(), m.scope())) + " "
+	queryText := prompt + m.renderInput()
+	if m.userQuery.IsEmpty() {
+		queryText = prompt + Styles.Cursor.Render(" ") + "(type to search...)"
 	}
-	queryBox := QueryBoxStyle.Render(queryText)
+	queryBox := Styles.QueryBox.Render(queryText)
 	b.WriteString(queryBox + "\n")
 }
 
+func (m *Model) renderInput() string {
+	value := m.userQuery.value
+	cursor := m.userQuery.cursor
+	if cursor >= len(value) {
+		return string(value) + Styles.Cursor.Render(" ")
+	}
+	return string(value[:cursor]) + Styles.Cursor.Render(string(value[cursor])) + string(value[cursor+1:])
+}
+
 func (m *Model) renderSeparator(b *strings.Builder) {
-	separator := SeparatorStyle.Render("────────────────────────────────────────")
+	separator := Styles.Separator.Render("────────────────────────────────────────")
 	b.WriteString(separator + "\n")
 }
 
-func (m *Model) renderPathList(b *strings.Builder) {
-	lastIdx := m.offset + m.height - 4
+func (m *Model) pathRows() []string {
+	lastIdx := m.offset + m.visibleRows()
 	if lastIdx > len(m.filteredPaths) {
 		lastIdx = len(m.filteredPaths)
 	}
 
+	opts := m.cfg.ScanFilterOptions(m.root)
+	query := m.userQuery.String()
+	rows := make([]string, 0, max(0, lastIdx-m.offset))
 	for i := m.offset; i < lastIdx; i++ {
-		line := ""
 		path := m.filteredPaths[i]
-		if i == m.cursor {
-			line = HighlightedFileStyle.Render(fmt.Sprintf("❯ %.1f  %s", path.Score, path.Path))
+		rows = append(rows, m.renderRow(path, i == m.cursor, scanengine.MatchPositions(opts, path.Path, query)))
+	}
+	return rows
+}
+
+// renderRow renders a result with the characters matching the query
+// highlighted. The cursor row keeps its colors behind the highlights.
+func (m *Model) renderRow(result scanengine.ScanFilteredResult, current bool, matches []int) string {
+	style := lipgloss.NewStyle()
+	mark := " "
+	if m.isMarked(result.Path) {
+		style = Styles.Marked
+		mark = "+"
+	}
+	var b strings.Builder
+	if current {
+		style = Styles.CursorRow
+		b.WriteString(style.Render(fmt.Sprintf("❯%s %.1f  ", mark, result.Score)))
+	} else {
+		b.WriteString(style.Render(" "+mark+" ") + Styles.Score.Inherit(style).Render(fmt.Sprintf("%.1f", result.Score)) + style.Render("  "))
+	}
+
+	runes := []rune(result.Path)
+	matched := make([]bool, len(runes))
+	for _, i := range matches {
+		matched[i] = true
+	}
+	match := Styles.Match.Inherit(style)
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		if matched[start] {
+			b.WriteString(match.Render(string(runes[start:end])))
 		} else {
-			line = fmt.Sprintf("  %.1f  %s", path.Score, path.Path)
+			b.WriteString(style.Render(string(runes[start:end])))
+		}
+		start = end
+	}
+	return b.String()
+}
+
+// helpRows lists the active key bindings in place of the results.
+func (m *Model) helpRows() []string {
+	bindings := m.keys.Bindings()
+	if len(bindings) > m.visibleRows() {
+		bindings = bindings[:m.visibleRows()]
+	}
+	rows := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		key := binding.Key
+		if key == " " {
+			key = "space"
 		}
-		b.WriteString(line + "\n")
+		description := keymap.Actions[binding.Actions[0].Name]
+		rows = append(rows, fmt.Sprintf("  %-14s %-24s %s", key, binding.Expr, description))
 	}
+	return rows
 }
 
 func (m *Model) renderStatus(b *strings.Builder) {
+	// Only the best maxFilteredResults matches are kept.
+	filtered := fmt.Sprint(m.matchCount)
+	if len(m.filteredPaths) < m.matchCount {
+		filtered = fmt.Sprintf("%d/%d shown", len(m.filteredPaths), m.matchCount)
+	}
 	var status string
 	if m.scanDone {
-		status = StatusStyle.Render(fmt.Sprintf("--- Scan Completed (%d); Filtered (%d) ---", len(m.scannedPaths), len(m.filteredPaths)))
+		status = Styles.Status.Render(fmt.Sprintf("--- Scan Completed (%d); Filtered (%s) ---", len(m.scannedPaths), filtered))
 	} else {
-		status = StatusStyle.Render(fmt.Sprintf("--- Scanning (%d); Filtered (%d) ---", len(m.scannedPaths), len(m.filteredPaths)))
+		status = Styles.Status.Render(fmt.Sprintf("--- Scanning (%d); Filtered (%s) ---", len(m.scannedPaths), filtered))
+	}
+	if m.filtering {
+		status = Styles.Status.Render(spinnerFrames[m.spinnerFrame]+" Filtering ") + status
+	}
+	if len(m.marks) > 0 {
+		status += Styles.Status.Render(fmt.Sprintf(" Marked (%d)", len(m.marks)))
+	}
+	if m.reloadErr != "" {
+		status += " " + Styles.Error.Render("Configuration not reloaded: "+m.reloadErr)
+	}
+	if m.keysErr != nil {
+		status += " " + Styles.Error.Render("Key bindings ignored: "+m.keysErr.Error())
+	}
+	switch {
+	case m.showHelp:
+		status += Styles.Status.Render(" Press any key to close the help")
+	case m.notice != "":
+		status += Styles.Status.Render(" " + m.notice)
 	}
 
 	b.WriteString(status)